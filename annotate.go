@@ -0,0 +1,202 @@
+package cld2
+
+import (
+	"bytes"
+	"sort"
+
+	"cld2/internal/htmlblock"
+)
+
+// AnnotateOptions control AnnotateLang.
+type AnnotateOptions struct {
+	Correct bool // also replace lang attributes that disagree with the text
+	Spans   bool // wrap parts of a block in another language in <span lang>
+}
+
+// AnnotateLang returns html with lang attributes where the detected
+// language differs from the language in effect. res must come from
+// DetectWithOptions on html with HTML, Spans and InputCoordinates set.
+//
+// Each block-level element, and each element with a lang attribute,
+// gets the language most of its text is in, by the spans of res. A block
+// that inherits another language gets a lang attribute, and one that
+// declares another is changed only with opts.Correct. The <html>
+// element gets the top estimate of res if reliable. With opts.Spans,
+// runs of text in other languages than their block are wrapped in
+// <span lang>. Languages are written as their code.
+func AnnotateLang(html []byte, res Languages, opts AnnotateOptions) []byte {
+	spans := append([]Span(nil), res.Spans...)
+	sort.SliceStable(spans, func(i, j int) bool { return spans[i].Offset < spans[j].Offset })
+
+	var edits []htmlEdit
+	type open struct {
+		end  int
+		lang string
+	}
+	var stack []open // enclosing blocks and the language in effect in them
+	for _, b := range htmlblock.Scan(html) {
+		for len(stack) > 0 && b.Offset >= stack[len(stack)-1].end {
+			stack = stack[:len(stack)-1]
+		}
+		inherited := ""
+		if len(stack) > 0 {
+			inherited = stack[len(stack)-1].lang
+		}
+		lang := inherited
+		if b.Declared {
+			lang = b.Lang
+		}
+
+		segs := textSegments(html, b.Text, spans)
+		want := majorityLanguage(segs)
+		if b.Tag == "html" && len(res.Estimates) > 0 && res.Reliable {
+			want = res.Estimates[0].Language
+		}
+		if want != UNKNOWN_LANGUAGE && LanguageFromTag(lang) != want {
+			code := want.Code()
+			switch {
+			case !b.Declared:
+				at := b.Offset + 1 + len(b.Tag)
+				edits = append(edits, htmlEdit{at: at, insert: ` lang="` + code + `"`})
+				lang = code
+			case opts.Correct && len(b.LangAttrs) > 0:
+				for _, r := range b.LangAttrs {
+					edits = append(edits, htmlEdit{at: r.Offset, remove: r.Length, insert: code})
+				}
+				lang = code
+			}
+		}
+		stack = append(stack, open{b.Offset + b.Length, lang})
+
+		if !opts.Spans {
+			continue
+		}
+		blockLang := LanguageFromTag(lang)
+		for _, s := range segs {
+			if s.Language == UNKNOWN_LANGUAGE || s.Language == blockLang {
+				continue
+			}
+			edits = append(edits,
+				htmlEdit{at: s.Offset, insert: `<span lang="` + s.Language.Code() + `">`},
+				htmlEdit{at: s.Offset + s.Length, insert: `</span>`})
+		}
+	}
+	return applyEdits(html, edits)
+}
+
+// textSegments splits the text ranges of a block by the spans over
+// them, trimmed of spaces and moved off entities. Text no span covers
+// is UNKNOWN_LANGUAGE. spans must be sorted by offset.
+func textSegments(html []byte, text []htmlblock.Range, spans []Span) []Span {
+	var segs []Span
+	add := func(start, end int, lang Language) {
+		start, end = trimSegment(html, start, end)
+		if n := len(segs); n > 0 {
+			// Widening may have reached into the segment before
+			if prev := segs[n-1].Offset + segs[n-1].Length; start < prev {
+				start = prev
+			}
+		}
+		if start < end {
+			segs = append(segs, Span{Offset: start, Length: end - start, Language: lang})
+		}
+	}
+	for _, r := range text {
+		pos, end := r.Offset, r.Offset+r.Length
+		i := sort.Search(len(spans), func(i int) bool { return spans[i].Offset+spans[i].Length > pos })
+		for ; i < len(spans) && pos < end; i++ {
+			s := spans[i]
+			if s.Offset >= end {
+				break
+			}
+			if s.Offset > pos {
+				add(pos, s.Offset, UNKNOWN_LANGUAGE)
+				pos = s.Offset
+			}
+			next := s.Offset + s.Length
+			if next > end {
+				next = end
+			}
+			add(pos, next, s.Language)
+			pos = next
+		}
+		if pos < end {
+			add(pos, end, UNKNOWN_LANGUAGE)
+		}
+	}
+	return segs
+}
+
+// trimSegment shrinks html[start:end] to leave out surrounding spaces,
+// and widens it so that it doesn't cut through a character reference.
+func trimSegment(html []byte, start, end int) (int, int) {
+	if amp := entityStart(html, start); amp >= 0 {
+		start = amp
+	}
+	if amp := entityStart(html, end); amp >= 0 {
+		if semi := bytes.IndexByte(html[amp:], ';'); semi >= 0 {
+			end = amp + semi + 1
+		}
+	}
+	for start < end && htmlblock.IsSpace(html[start]) {
+		start++
+	}
+	for end > start && htmlblock.IsSpace(html[end-1]) {
+		end--
+	}
+	return start, end
+}
+
+// entityStart returns the offset of the '&' of the character reference
+// that i is inside, or -1.
+func entityStart(html []byte, i int) int {
+	for j := i - 1; j >= 0 && i-j <= 32; j-- {
+		c := html[j]
+		switch {
+		case c == '&':
+			return j
+		case c == '#' || htmlblock.IsASCIILetter(c) || '0' <= c && c <= '9':
+		default:
+			return -1
+		}
+	}
+	return -1
+}
+
+// majorityLanguage returns the language of most bytes of segs, or
+// UNKNOWN_LANGUAGE if none is known.
+func majorityLanguage(segs []Span) Language {
+	count := make(map[Language]int)
+	best := UNKNOWN_LANGUAGE
+	for _, s := range segs {
+		if s.Language == UNKNOWN_LANGUAGE {
+			continue
+		}
+		count[s.Language] += s.Length
+		if best == UNKNOWN_LANGUAGE || count[s.Language] > count[best] {
+			best = s.Language
+		}
+	}
+	return best
+}
+
+// htmlEdit replaces remove bytes at offset at with insert.
+type htmlEdit struct {
+	at     int
+	remove int
+	insert string
+}
+
+// applyEdits applies edits, which must not overlap, to html. Edits at
+// the same offset are applied in the order given.
+func applyEdits(html []byte, edits []htmlEdit) []byte {
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].at < edits[j].at })
+	out := make([]byte, 0, len(html)+len(edits)*16)
+	pos := 0
+	for _, e := range edits {
+		out = append(out, html[pos:e.at]...)
+		out = append(out, e.insert...)
+		pos = e.at + e.remove
+	}
+	return append(out, html[pos:]...)
+}
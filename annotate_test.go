@@ -0,0 +1,60 @@
+package cld2
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAnnotateLang(t *testing.T) {
+	html := `<html><body><p>Hello there, friend.</p><p lang="en">Guten Tag &amp; willkommen. Hello again.</p><div>Bonjour à tous</div></body></html>`
+	// spanAt returns a span over the first occurrence of s in html.
+	spanAt := func(s string, lang Language) Span {
+		i := strings.Index(html, s)
+		if i < 0 {
+			t.Fatalf("%q not in html", s)
+		}
+		return Span{Offset: i, Length: len(s), Language: lang}
+	}
+	res := Languages{
+		Estimates: []Estimate{{Language: ENGLISH, Percent: 60}},
+		Reliable:  true,
+		Spans: []Span{
+			spanAt("Hello there, friend.", ENGLISH),
+			// Cut inside the entity, as CLD2's chunks can be
+			spanAt("Guten Tag &am", GERMAN),
+			spanAt("p; willkommen. ", GERMAN),
+			spanAt("Hello again.", ENGLISH),
+			spanAt("Bonjour à tous", FRENCH),
+		},
+	}
+
+	got := string(AnnotateLang([]byte(html), res, AnnotateOptions{}))
+	want := `<html lang="en"><body><p>Hello there, friend.</p><p lang="en">Guten Tag &amp; willkommen. Hello again.</p><div lang="fr">Bonjour à tous</div></body></html>`
+	if got != want {
+		t.Errorf("want\n%s\ngot\n%s", want, got)
+	}
+
+	got = string(AnnotateLang([]byte(html), res, AnnotateOptions{Correct: true, Spans: true}))
+	want = `<html lang="en"><body><p>Hello there, friend.</p><p lang="de">Guten Tag &amp; willkommen. <span lang="en">Hello again.</span></p><div lang="fr">Bonjour à tous</div></body></html>`
+	if got != want {
+		t.Errorf("want\n%s\ngot\n%s", want, got)
+	}
+
+	// Nothing to change
+	res.Spans = res.Spans[:1]
+	doc := `<html lang="en"><p>Hello there, friend.</p></html>`
+	res.Spans[0].Offset = strings.Index(doc, "Hello")
+	if got := string(AnnotateLang([]byte(doc), res, AnnotateOptions{Correct: true, Spans: true})); got != doc {
+		t.Errorf("want document unchanged, got %s", got)
+	}
+}
+
+func TestTrimSegment(t *testing.T) {
+	html := []byte(" a &amp; b ")
+	if s, e := trimSegment(html, 0, 6); string(html[s:e]) != "a &amp;" {
+		t.Errorf("want segment widened past the entity, got %q", html[s:e])
+	}
+	if s, e := trimSegment(html, 5, len(html)); string(html[s:e]) != "&amp; b" {
+		t.Errorf("want segment widened to the entity start, got %q", html[s:e])
+	}
+}
@@ -0,0 +1,186 @@
+package cld2
+
+//go:generate go test -run TestGenerateCalibration -gen-calibration
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+)
+
+// Calibration turns what CLD2 reports about an estimate into a
+// confidence, 0..1, that the estimate is right, by logistic regression
+// on a few features of the result. The weights are fitted by
+// FitCalibration on labeled text like the text to be detected. Scripts
+// differ in how many close languages share them, so each script column
+// has its own weights: Latin, Cyrillic, Arabic and everything else.
+//
+// A Calibration is plain data; it can be saved and loaded as JSON.
+type Calibration struct {
+	Scripts [4]CalibrationWeights `json:"scripts"`
+}
+
+// CalibrationWeights are the logistic regression weights for one script.
+type CalibrationWeights struct {
+	Bias        float64 `json:"bias"`
+	Reliability float64 `json:"reliability"` // CLD2's reliability of the estimate, 0..1
+	Closeness   float64 `json:"closeness"`   // how close NormScore is to the language average, 0..1
+	Share       float64 `json:"share"`       // Percent / 100
+	Margin      float64 `json:"margin"`      // Percent ahead of the best other estimate / 100
+	Length      float64 `json:"length"`      // log2(1+TextBytes) / 16
+}
+
+// numCalibrationFeatures is the number of weights in CalibrationWeights.
+const numCalibrationFeatures = 6
+
+func (w CalibrationWeights) vector() [numCalibrationFeatures]float64 {
+	return [...]float64{w.Bias, w.Reliability, w.Closeness, w.Share, w.Margin, w.Length}
+}
+
+func weightsFromVector(v [numCalibrationFeatures]float64) CalibrationWeights {
+	return CalibrationWeights{v[0], v[1], v[2], v[3], v[4], v[5]}
+}
+
+// calibrationFeatures describe one estimate. The first feature is
+// always 1, for the bias.
+type calibrationFeatures struct {
+	script      int
+	x           [numCalibrationFeatures]float64
+	reliability int     // CLD2's reliability 0..100
+	ratio       float64 // NormScore over the language average, or 1 if unknown
+}
+
+// confidence returns the confidence score for features f.
+func (c *Calibration) confidence(f calibrationFeatures) float64 {
+	w := c.Scripts[f.script].vector()
+	z := 0.0
+	for i := range w {
+		z += w[i] * f.x[i]
+	}
+	return 1 / (1 + math.Exp(-z))
+}
+
+// apply sets the Confidence of each estimate in res.
+func (c *Calibration) apply(res Languages, fs []calibrationFeatures) {
+	for i := range res.Estimates {
+		res.Estimates[i].Confidence = c.confidence(fs[i])
+	}
+}
+
+// LoadCalibration reads a Calibration saved as JSON, such as one
+// returned by FitCalibration and encoded with encoding/json.
+func LoadCalibration(r io.Reader) (*Calibration, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	c := new(Calibration)
+	if err := dec.Decode(c); err != nil {
+		return nil, fmt.Errorf("cld2: cannot load calibration: %v", err)
+	}
+	return c, nil
+}
+
+// estimateFeatures returns the calibration features of each estimate in
+// res. reliability holds CLD2's reliability 0..100 of each estimate,
+// expected the average score of real text in its language, and script
+// the script column of the text.
+func estimateFeatures(res Languages, reliability, expected []int, script int) []calibrationFeatures {
+	fs := make([]calibrationFeatures, len(res.Estimates))
+	length := math.Log2(1+float64(res.TextBytes)) / 16
+	for i, e := range res.Estimates {
+		other := 0
+		for j, o := range res.Estimates {
+			if j != i && o.Percent > other {
+				other = o.Percent
+			}
+		}
+		fs[i] = calibrationFeatures{script: script, x: [...]float64{
+			1,
+			float64(reliability[i]) / 100,
+			reliabilityExpected(e.NormScore, float64(expected[i])),
+			float64(e.Percent) / 100,
+			float64(e.Percent-other) / 100,
+			length,
+		}, reliability: reliability[i], ratio: 1}
+		if expected[i] > 0 {
+			fs[i].ratio = e.NormScore / float64(expected[i])
+		}
+	}
+	return fs
+}
+
+// LabeledText is a text and the language it is known to be in.
+type LabeledText struct {
+	Text     []byte
+	Language Language
+}
+
+// calibrationObs is one estimate seen while fitting, and whether it
+// was the right language.
+type calibrationObs struct {
+	features calibrationFeatures
+	correct  bool
+}
+
+// Fitting settings: plain gradient descent on the mean log loss with a
+// small L2 penalty. Scripts with fewer observations than minScriptObs
+// share the weights fitted on all scripts together.
+const (
+	fitIterations = 2000
+	fitRate       = 1.0
+	fitL2         = 0.001
+	minScriptObs  = 50
+)
+
+// fitCalibration fits a Calibration to observations.
+func fitCalibration(obs []calibrationObs) *Calibration {
+	c := new(Calibration)
+	all := fitWeights(obs)
+	for s := range c.Scripts {
+		var sub []calibrationObs
+		for _, o := range obs {
+			if o.features.script == s {
+				sub = append(sub, o)
+			}
+		}
+		if len(sub) < minScriptObs {
+			c.Scripts[s] = all
+			continue
+		}
+		c.Scripts[s] = fitWeights(sub)
+	}
+	return c
+}
+
+// fitWeights fits one set of logistic regression weights.
+func fitWeights(obs []calibrationObs) CalibrationWeights {
+	var w [numCalibrationFeatures]float64
+	if len(obs) == 0 {
+		return weightsFromVector(w)
+	}
+	for it := 0; it < fitIterations; it++ {
+		var grad [numCalibrationFeatures]float64
+		for _, o := range obs {
+			z := 0.0
+			for i := range w {
+				z += w[i] * o.features.x[i]
+			}
+			p := 1 / (1 + math.Exp(-z))
+			y := 0.0
+			if o.correct {
+				y = 1
+			}
+			for i := range grad {
+				grad[i] += (p - y) * o.features.x[i]
+			}
+		}
+		for i := range w {
+			g := grad[i] / float64(len(obs))
+			if i > 0 {
+				g += fitL2 * w[i]
+			}
+			w[i] -= fitRate * g
+		}
+	}
+	return weightsFromVector(w)
+}
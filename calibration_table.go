@@ -0,0 +1,7 @@
+package cld2
+
+// defaultCalibration is used when Options.Calibration is nil. "go
+// generate" fits it on the samples in unittest_data.h, which needs the
+// full CLD2 scoring tables. Until then it is nil and Estimate.Confidence
+// is left 0 unless a Calibration is given.
+var defaultCalibration *Calibration
@@ -0,0 +1,152 @@
+package cld2
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestEstimateFeatures(t *testing.T) {
+	res := Languages{
+		Estimates: []Estimate{
+			{Language: GERMAN, Percent: 70, NormScore: 1000},
+			{Language: ENGLISH, Percent: 30, NormScore: 100},
+		},
+		TextBytes: 255,
+	}
+	fs := estimateFeatures(res, []int{90, 20}, []int{1000, 1000}, 1)
+	want := [][numCalibrationFeatures]float64{
+		{1, 0.9, 1, 0.7, 0.4, 0.5},
+		{1, 0.2, 0, 0.3, -0.4, 0.5},
+	}
+	for i, f := range fs {
+		if f.script != 1 {
+			t.Errorf("estimate %d: want script 1, got %d", i, f.script)
+		}
+		for j := range f.x {
+			if math.Abs(f.x[j]-want[i][j]) > 1e-9 {
+				t.Errorf("estimate %d: want features %v, got %v", i, want[i], f.x)
+				break
+			}
+		}
+	}
+}
+
+func TestFitCalibration(t *testing.T) {
+	// Estimates are right 90% of the time with high reliability and
+	// 30% of the time with low reliability.
+	var obs []calibrationObs
+	for i := 0; i < 100; i++ {
+		hi := calibrationFeatures{script: 0, x: [...]float64{1, 1, 1, 1, 1, 0.5}}
+		lo := calibrationFeatures{script: 0, x: [...]float64{1, 0, 1, 1, 1, 0.5}}
+		obs = append(obs, calibrationObs{hi, i%10 != 0}, calibrationObs{lo, i%10 < 3})
+	}
+	c := fitCalibration(obs)
+	hi := c.confidence(obs[0].features)
+	lo := c.confidence(obs[1].features)
+	if math.Abs(hi-0.9) > 0.05 || math.Abs(lo-0.3) > 0.05 {
+		t.Errorf("want confidences near 0.9 and 0.3, got %.3f and %.3f", hi, lo)
+	}
+	// Too few observations in the other scripts to fit them separately.
+	if c.Scripts[2] != c.Scripts[0] {
+		t.Errorf("want sparse scripts to share weights, got %+v", c.Scripts)
+	}
+}
+
+// testCalibration favors long, reliable, single-language text in every
+// script.
+var testCalibration = Calibration{
+	Scripts: [4]CalibrationWeights{
+		{Bias: -5, Reliability: 3, Closeness: 1.5, Share: 2, Margin: 1.5, Length: 3},
+		{Bias: -5, Reliability: 3, Closeness: 1.5, Share: 2, Margin: 1.5, Length: 3},
+		{Bias: -5, Reliability: 3, Closeness: 1.5, Share: 2, Margin: 1.5, Length: 3},
+		{Bias: -5, Reliability: 3, Closeness: 1.5, Share: 2, Margin: 1.5, Length: 3},
+	},
+}
+
+func TestLoadCalibration(t *testing.T) {
+	b, err := json.Marshal(testCalibration)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := LoadCalibration(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *c != testCalibration {
+		t.Errorf("want %+v after round trip, got %+v", testCalibration, *c)
+	}
+	if _, err := LoadCalibration(strings.NewReader(`{"scripts":[],"slope":1}`)); err == nil {
+		t.Error("want error for unknown field")
+	}
+}
+
+func TestConfidenceFeatures(t *testing.T) {
+	long := calibrationFeatures{x: [...]float64{1, 1, 1, 1, 1, 0.7}}
+	short := calibrationFeatures{x: [...]float64{1, 0.3, 0.5, 1, 1, 0.25}}
+	second := calibrationFeatures{x: [...]float64{1, 0.5, 0.5, 0.2, -0.6, 0.7}}
+	l, s, r := testCalibration.confidence(long), testCalibration.confidence(short), testCalibration.confidence(second)
+	if !(l > 0.95 && s < l && r < 0.5) {
+		t.Errorf("want confident long text, less confident short text and unlikely runner-up, got %.3f %.3f %.3f", l, s, r)
+	}
+}
+
+func TestUnittestSamples(t *testing.T) {
+	src, err := ioutil.ReadFile("unittest_data.h")
+	if err != nil {
+		t.Fatal(err)
+	}
+	samples := unittestSamples(src)
+	if len(samples) < 500 {
+		t.Fatalf("want at least 500 samples, got %d", len(samples))
+	}
+	for _, s := range samples {
+		if s.Language == DANISH && strings.HasPrefix(string(s.Text), "a z tallene") {
+			return
+		}
+	}
+	t.Errorf("want the Danish sample, got %d samples without it", len(samples))
+}
+
+// sampleRe matches a one-line test string of unittest_data.h:
+//
+//	const char* kTeststr_da_Latn = " ...";
+var sampleRe = regexp.MustCompile(`^const char\* kTeststr_([a-z]+)_[A-Z][a-z]{3}\d* = +("(?:[^"\\]|\\.)*");$`)
+
+// unittestSamples returns the labeled test strings of unittest_data.h,
+// each also cut at a space near 16, 32, 64 and 128 bytes, where CLD2 is
+// often wrong.
+func unittestSamples(src []byte) []LabeledText {
+	var samples []LabeledText
+	for _, line := range strings.Split(string(src), "\n") {
+		if strings.HasPrefix(line, "#else") {
+			// The same strings again, with escapes
+			break
+		}
+		m := sampleRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		lang := LanguageFromCode(m[1])
+		text, err := strconv.Unquote(m[2])
+		if lang == UNKNOWN_LANGUAGE || err != nil {
+			continue
+		}
+		text = strings.TrimSpace(text)
+		samples = append(samples, LabeledText{[]byte(text), lang})
+		for _, n := range []int{16, 32, 64, 128} {
+			if n >= len(text) {
+				break
+			}
+			if i := strings.LastIndexByte(text[:n], ' '); i > 0 {
+				samples = append(samples, LabeledText{[]byte(text[:i]), lang})
+			}
+		}
+	}
+	return samples
+}
@@ -0,0 +1,573 @@
+package cld2
+
+//go:generate go run gen_charsets.go
+
+import (
+	"fmt"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// The decoders below follow the WHATWG Encoding Standard, which is what
+// browsers do with legacy pages. Malformed input becomes U+FFFD.
+
+// singleByteTables holds the code page of each single-byte encoding.
+// ISO-8859-9 and ISO-8859-11 are decoded as their Windows supersets.
+var singleByteTables = map[Encoding]*[128]uint16{
+	ISO_8859_2:      &iso8859_2,
+	ISO_8859_3:      &iso8859_3,
+	ISO_8859_4:      &iso8859_4,
+	ISO_8859_5:      &iso8859_5,
+	ISO_8859_6:      &iso8859_6,
+	ISO_8859_7:      &iso8859_7,
+	ISO_8859_8:      &iso8859_8,
+	ISO_8859_8_I:    &iso8859_8,
+	HEBREW_VISUAL:   &iso8859_8,
+	ISO_8859_9:      &windows1254,
+	ISO_8859_10:     &iso8859_10,
+	ISO_8859_11:     &windows874,
+	ISO_8859_13:     &iso8859_13,
+	ISO_8859_15:     &iso8859_15,
+	RUSSIAN_KOI8_R:  &koi8r,
+	RUSSIAN_KOI8_RU: &koi8u,
+	RUSSIAN_CP866:   &ibm866,
+	MACINTOSH_ROMAN: &macintosh,
+	MSFT_CP874:      &windows874,
+	MSFT_CP1250:     &windows1250,
+	RUSSIAN_CP1251:  &windows1251,
+	MSFT_CP1252:     &windows1252,
+	MSFT_CP1253:     &windows1253,
+	MSFT_CP1254:     &windows1254,
+	MSFT_CP1255:     &windows1255,
+	MSFT_CP1256:     &windows1256,
+	MSFT_CP1257:     &windows1257,
+}
+
+// toUTF8 converts data from encoding enc to UTF-8.
+func toUTF8(data []byte, enc Encoding) ([]byte, error) {
+	if table, ok := singleByteTables[enc]; ok {
+		return decodeSingleByte(data, table), nil
+	}
+	switch enc {
+	case UTF8, ASCII_7BIT:
+		return data, nil
+	case ISO_8859_1:
+		return decodeLatin1(data), nil
+	case JAPANESE_SHIFT_JIS, JAPANESE_CP932,
+		KDDI_SHIFT_JIS, DOCOMO_SHIFT_JIS, SOFTBANK_SHIFT_JIS:
+		return decodeShiftJIS(data), nil
+	case JAPANESE_EUC_JP:
+		return decodeEUCJP(data), nil
+	case JAPANESE_JIS, KDDI_ISO_2022_JP, SOFTBANK_ISO_2022_JP:
+		return decodeISO2022JP(data), nil
+	case CHINESE_BIG5, CHINESE_BIG5_CP950, BIG5_HKSCS:
+		return decodeBig5(data), nil
+	case CHINESE_GB, GBK, GB18030:
+		return decodeGB18030(data), nil
+	case HZ_GB_2312:
+		return decodeHZ(data), nil
+	case KOREAN_EUC_KR:
+		return decodeEUCKR(data), nil
+	case UTF16BE, UTF16LE:
+		return decodeUTF16(data, enc == UTF16BE), nil
+	case UTF32BE, UTF32LE:
+		return decodeUTF32(data, enc == UTF32BE), nil
+	}
+	return nil, fmt.Errorf("cld2: cannot convert %v to UTF-8", enc)
+}
+
+// trimPartialChar drops a character of encoding enc cut off at the end
+// of data, as when data is the start of a longer document, so that it
+// isn't decoded as U+FFFD. Double-byte encodings are scanned from the
+// start, since their trail bytes can look like lead bytes. Stateful
+// encodings such as ISO-2022-JP are left as they are.
+func trimPartialChar(data []byte, enc Encoding) []byte {
+	var size func(i int) int // bytes in the character starting at data[i]
+	switch enc {
+	case UTF8:
+		for i := 1; i < utf8.UTFMax && i <= len(data); i++ {
+			if utf8.RuneStart(data[len(data)-i]) {
+				if !utf8.FullRune(data[len(data)-i:]) {
+					return data[:len(data)-i]
+				}
+				break
+			}
+		}
+		return data
+	case UTF16BE, UTF16LE:
+		n := len(data) &^ 1
+		if n >= 2 {
+			// The byte order mark wins, as in decodeUTF16
+			bigEndian := enc == UTF16BE
+			if data[0] == 0xfe && data[1] == 0xff || data[0] == 0xff && data[1] == 0xfe {
+				bigEndian = data[0] == 0xfe
+			}
+			last := uint16(data[n-1])<<8 | uint16(data[n-2])
+			if bigEndian {
+				last = uint16(data[n-2])<<8 | uint16(data[n-1])
+			}
+			if last >= 0xd800 && last < 0xdc00 {
+				n -= 2 // a high surrogate without its low one
+			}
+		}
+		return data[:n]
+	case UTF32BE, UTF32LE:
+		return data[:len(data)&^3]
+	case JAPANESE_SHIFT_JIS, JAPANESE_CP932,
+		KDDI_SHIFT_JIS, DOCOMO_SHIFT_JIS, SOFTBANK_SHIFT_JIS:
+		size = func(i int) int {
+			if b := data[i]; b >= 0x81 && b <= 0x9f || b >= 0xe0 && b <= 0xfc {
+				return 2
+			}
+			return 1
+		}
+	case JAPANESE_EUC_JP:
+		size = func(i int) int {
+			switch b := data[i]; {
+			case b == 0x8f:
+				return 3
+			case b == 0x8e || isEUCByte(b):
+				return 2
+			}
+			return 1
+		}
+	case CHINESE_BIG5, CHINESE_BIG5_CP950, BIG5_HKSCS, KOREAN_EUC_KR:
+		size = func(i int) int {
+			if b := data[i]; b >= 0x81 && b <= 0xfe {
+				return 2
+			}
+			return 1
+		}
+	case CHINESE_GB, GBK, GB18030:
+		size = func(i int) int {
+			switch b := data[i]; {
+			case b < 0x81 || b == 0xff:
+				return 1
+			case i+1 < len(data) && data[i+1] >= 0x30 && data[i+1] <= 0x39:
+				return 4
+			}
+			return 2
+		}
+	default:
+		return data
+	}
+	for i := 0; i < len(data); {
+		n := size(i)
+		if i+n > len(data) {
+			return data[:i]
+		}
+		i += n
+	}
+	return data
+}
+
+func decodeLatin1(data []byte) []byte {
+	out := make([]byte, 0, len(data)+len(data)/4)
+	for _, b := range data {
+		out = utf8.AppendRune(out, rune(b))
+	}
+	return out
+}
+
+func decodeSingleByte(data []byte, table *[128]uint16) []byte {
+	out := make([]byte, 0, len(data)+len(data)/2)
+	for _, b := range data {
+		if b < 0x80 {
+			out = append(out, b)
+		} else {
+			out = utf8.AppendRune(out, indexRune(rune(table[b-0x80])))
+		}
+	}
+	return out
+}
+
+func decodeShiftJIS(data []byte) []byte {
+	out := make([]byte, 0, len(data)*3/2)
+	for i := 0; i < len(data); i++ {
+		b := data[i]
+		switch {
+		case b <= 0x80:
+			out = utf8.AppendRune(out, rune(b))
+		case b >= 0xa1 && b <= 0xdf:
+			out = utf8.AppendRune(out, 0xff61+rune(b)-0xa1)
+		case (b >= 0x81 && b <= 0x9f || b >= 0xe0 && b <= 0xfc) && i+1 < len(data):
+			t := data[i+1]
+			r := utf8.RuneError
+			if t >= 0x40 && t <= 0x7e || t >= 0x80 && t <= 0xfc {
+				lead, off := 0x81, 0x40
+				if b >= 0xa0 {
+					lead = 0xc1
+				}
+				if t >= 0x7f {
+					off = 0x41
+				}
+				p := (int(b)-lead)*188 + int(t) - off
+				if p >= 8836 && p <= 10715 {
+					r = 0xe000 + rune(p-8836)
+				} else if p < len(jis0208) {
+					r = indexRune(rune(jis0208[p]))
+				}
+			}
+			if r != utf8.RuneError || t >= 0x80 {
+				i++
+			}
+			out = utf8.AppendRune(out, r)
+		default:
+			out = utf8.AppendRune(out, utf8.RuneError)
+		}
+	}
+	return out
+}
+
+func decodeEUCJP(data []byte) []byte {
+	out := make([]byte, 0, len(data)*3/2)
+	for i := 0; i < len(data); i++ {
+		b := data[i]
+		switch {
+		case b < 0x80:
+			out = append(out, b)
+		case b == 0x8e && i+1 < len(data) && data[i+1] >= 0xa1 && data[i+1] <= 0xdf:
+			out = utf8.AppendRune(out, 0xff61+rune(data[i+1])-0xa1)
+			i++
+		case b == 0x8f && i+2 < len(data) && isEUCByte(data[i+1]) && isEUCByte(data[i+2]):
+			p := (int(data[i+1])-0xa1)*94 + int(data[i+2]) - 0xa1
+			r := utf8.RuneError
+			if p < len(jis0212) {
+				r = indexRune(rune(jis0212[p]))
+			}
+			out = utf8.AppendRune(out, r)
+			i += 2
+		case isEUCByte(b) && i+1 < len(data) && isEUCByte(data[i+1]):
+			p := (int(b)-0xa1)*94 + int(data[i+1]) - 0xa1
+			r := utf8.RuneError
+			if p < len(jis0208) {
+				r = indexRune(rune(jis0208[p]))
+			}
+			out = utf8.AppendRune(out, r)
+			i++
+		default:
+			out = utf8.AppendRune(out, utf8.RuneError)
+		}
+	}
+	return out
+}
+
+func isEUCByte(b byte) bool {
+	return b >= 0xa1 && b <= 0xfe
+}
+
+func decodeISO2022JP(data []byte) []byte {
+	const (
+		ascii = iota
+		roman
+		katakana
+		jis
+	)
+	out := make([]byte, 0, len(data)*3/2)
+	state := ascii
+	for i := 0; i < len(data); i++ {
+		b := data[i]
+		if b == 0x1b && i+2 < len(data) {
+			switch string(data[i+1 : i+3]) {
+			case "(B":
+				state = ascii
+			case "(J":
+				state = roman
+			case "(I":
+				state = katakana
+			case "$@", "$B":
+				state = jis
+			default:
+				out = utf8.AppendRune(out, utf8.RuneError)
+				continue
+			}
+			i += 2
+			continue
+		}
+		if b >= 0x80 || b == 0x0e || b == 0x0f || b == 0x1b {
+			out = utf8.AppendRune(out, utf8.RuneError)
+			continue
+		}
+		if b == '\n' || b == '\r' {
+			out = append(out, b)
+			continue
+		}
+		switch state {
+		case ascii:
+			out = append(out, b)
+		case roman:
+			switch b {
+			case 0x5c:
+				out = utf8.AppendRune(out, 0xa5)
+			case 0x7e:
+				out = utf8.AppendRune(out, 0x203e)
+			default:
+				out = append(out, b)
+			}
+		case katakana:
+			if b >= 0x21 && b <= 0x5f {
+				out = utf8.AppendRune(out, 0xff61+rune(b)-0x21)
+			} else {
+				out = utf8.AppendRune(out, utf8.RuneError)
+			}
+		case jis:
+			if b < 0x21 || b > 0x7e || i+1 >= len(data) {
+				out = utf8.AppendRune(out, utf8.RuneError)
+				continue
+			}
+			t := data[i+1]
+			r := utf8.RuneError
+			if t >= 0x21 && t <= 0x7e {
+				p := (int(b)-0x21)*94 + int(t) - 0x21
+				if p < len(jis0208) {
+					r = indexRune(rune(jis0208[p]))
+				}
+				i++
+			}
+			out = utf8.AppendRune(out, r)
+		}
+	}
+	return out
+}
+
+func decodeBig5(data []byte) []byte {
+	out := make([]byte, 0, len(data)*3/2)
+	for i := 0; i < len(data); i++ {
+		b := data[i]
+		switch {
+		case b < 0x80:
+			out = append(out, b)
+		case b >= 0x81 && b <= 0xfe && i+1 < len(data):
+			t := data[i+1]
+			if !(t >= 0x40 && t <= 0x7e || t >= 0xa1 && t <= 0xfe) {
+				out = utf8.AppendRune(out, utf8.RuneError)
+				if t >= 0x80 {
+					i++
+				}
+				continue
+			}
+			off := 0x40
+			if t >= 0x7f {
+				off = 0x62
+			}
+			p := (int(b)-0x81)*157 + int(t) - off
+			i++
+			// Four pointers decode to a letter plus combining mark.
+			switch p {
+			case 1133:
+				out = append(out, "Ê̄"...)
+			case 1135:
+				out = append(out, "Ê̌"...)
+			case 1164:
+				out = append(out, "ê̄"...)
+			case 1166:
+				out = append(out, "ê̌"...)
+			default:
+				r := utf8.RuneError
+				if p < len(big5) {
+					r = indexRune(big5[p])
+				}
+				out = utf8.AppendRune(out, r)
+			}
+		default:
+			out = utf8.AppendRune(out, utf8.RuneError)
+		}
+	}
+	return out
+}
+
+func decodeGB18030(data []byte) []byte {
+	out := make([]byte, 0, len(data)*3/2)
+	for i := 0; i < len(data); i++ {
+		b := data[i]
+		switch {
+		case b < 0x80:
+			out = append(out, b)
+		case b == 0x80:
+			out = utf8.AppendRune(out, 0x20ac)
+		case b == 0xff || i+1 >= len(data):
+			out = utf8.AppendRune(out, utf8.RuneError)
+		case data[i+1] >= 0x30 && data[i+1] <= 0x39:
+			// Four-byte sequence
+			if i+3 >= len(data) || data[i+2] < 0x81 || data[i+2] > 0xfe ||
+				data[i+3] < 0x30 || data[i+3] > 0x39 {
+				out = utf8.AppendRune(out, utf8.RuneError)
+				continue
+			}
+			p := ((int(b)-0x81)*10+int(data[i+1])-0x30)*1260 +
+				(int(data[i+2])-0x81)*10 + int(data[i+3]) - 0x30
+			out = utf8.AppendRune(out, gb18030RangesRune(p))
+			i += 3
+		default:
+			t := data[i+1]
+			r := utf8.RuneError
+			if t >= 0x40 && t <= 0x7e || t >= 0x80 && t <= 0xfe {
+				off := 0x40
+				if t >= 0x7f {
+					off = 0x41
+				}
+				p := (int(b)-0x81)*190 + int(t) - off
+				if p < len(gb18030) {
+					r = indexRune(rune(gb18030[p]))
+				}
+			}
+			if r != utf8.RuneError || t >= 0x80 {
+				i++
+			}
+			out = utf8.AppendRune(out, r)
+		}
+	}
+	return out
+}
+
+// gb18030RangesRune decodes a four-byte GB18030 pointer.
+func gb18030RangesRune(p int) rune {
+	switch {
+	case p == 7457:
+		return 0xe7c7
+	case p >= 189000 && p <= 1237575:
+		return 0x10000 + rune(p-189000)
+	case p > 39419:
+		return utf8.RuneError
+	}
+	lo, hi := 0, len(gb18030Ranges)
+	for hi-lo > 1 {
+		mid := (lo + hi) / 2
+		if int(gb18030Ranges[mid][0]) <= p {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return rune(gb18030Ranges[lo][1]) + rune(p-int(gb18030Ranges[lo][0]))
+}
+
+func decodeHZ(data []byte) []byte {
+	out := make([]byte, 0, len(data)*3/2)
+	gb := false
+	for i := 0; i < len(data); i++ {
+		b := data[i]
+		if b == '~' && i+1 < len(data) {
+			switch data[i+1] {
+			case '~':
+				out = append(out, '~')
+				i++
+				continue
+			case '{':
+				gb = true
+				i++
+				continue
+			case '}':
+				gb = false
+				i++
+				continue
+			case '\n':
+				i++
+				continue
+			}
+		}
+		if !gb || b < 0x21 || b > 0x7e {
+			if b >= 0x80 {
+				out = utf8.AppendRune(out, utf8.RuneError)
+			} else {
+				out = append(out, b)
+			}
+			continue
+		}
+		r := utf8.RuneError
+		if i+1 < len(data) && data[i+1] >= 0x21 && data[i+1] <= 0x7e {
+			p := (int(b|0x80)-0x81)*190 + int(data[i+1]|0x80) - 0x41
+			if p < len(gb18030) {
+				r = indexRune(rune(gb18030[p]))
+			}
+			i++
+		}
+		out = utf8.AppendRune(out, r)
+	}
+	return out
+}
+
+func decodeEUCKR(data []byte) []byte {
+	out := make([]byte, 0, len(data)*3/2)
+	for i := 0; i < len(data); i++ {
+		b := data[i]
+		switch {
+		case b < 0x80:
+			out = append(out, b)
+		case b >= 0x81 && b <= 0xfe && i+1 < len(data):
+			t := data[i+1]
+			r := utf8.RuneError
+			if t >= 0x41 && t <= 0xfe {
+				p := (int(b)-0x81)*190 + int(t) - 0x41
+				if p < len(euckr) {
+					r = indexRune(rune(euckr[p]))
+				}
+			}
+			if r != utf8.RuneError || t >= 0x80 {
+				i++
+			}
+			out = utf8.AppendRune(out, r)
+		default:
+			out = utf8.AppendRune(out, utf8.RuneError)
+		}
+	}
+	return out
+}
+
+// decodeUTF16 decodes UTF-16 in the byte order given unless data starts
+// with a byte order mark, which wins: the label "utf-16" says nothing
+// about the order, and as in browsers the mark overrides the label.
+func decodeUTF16(data []byte, bigEndian bool) []byte {
+	switch {
+	case len(data) >= 2 && data[0] == 0xfe && data[1] == 0xff:
+		bigEndian = true
+		data = data[2:]
+	case len(data) >= 2 && data[0] == 0xff && data[1] == 0xfe:
+		bigEndian = false
+		data = data[2:]
+	}
+	units := make([]uint16, 0, len(data)/2)
+	for i := 0; i+1 < len(data); i += 2 {
+		if bigEndian {
+			units = append(units, uint16(data[i])<<8|uint16(data[i+1]))
+		} else {
+			units = append(units, uint16(data[i+1])<<8|uint16(data[i]))
+		}
+	}
+	out := make([]byte, 0, len(data)*3/2)
+	for _, r := range utf16.Decode(units) {
+		out = utf8.AppendRune(out, r)
+	}
+	if len(data)%2 != 0 {
+		out = utf8.AppendRune(out, utf8.RuneError)
+	}
+	return out
+}
+
+func decodeUTF32(data []byte, bigEndian bool) []byte {
+	out := make([]byte, 0, len(data))
+	for i := 0; i+3 < len(data); i += 4 {
+		var r rune
+		if bigEndian {
+			r = rune(data[i])<<24 | rune(data[i+1])<<16 | rune(data[i+2])<<8 | rune(data[i+3])
+		} else {
+			r = rune(data[i+3])<<24 | rune(data[i+2])<<16 | rune(data[i+1])<<8 | rune(data[i])
+		}
+		if i == 0 && r == 0xfeff {
+			continue
+		}
+		out = utf8.AppendRune(out, r)
+	}
+	if len(data)%4 != 0 {
+		out = utf8.AppendRune(out, utf8.RuneError)
+	}
+	return out
+}
+
+// indexRune maps the zero entries of the index tables to U+FFFD.
+func indexRune(r rune) rune {
+	if r == 0 {
+		return utf8.RuneError
+	}
+	return r
+}
@@ -0,0 +1,103 @@
+package cld2
+
+import "testing"
+
+func TestParseEncoding(t *testing.T) {
+	for _, tc := range []struct {
+		charset string
+		want    Encoding
+	}{
+		{"Shift_JIS", JAPANESE_SHIFT_JIS},
+		{"ISO-2022-JP", JAPANESE_JIS},
+		{"windows-1251", RUSSIAN_CP1251},
+		{" \"UTF-8\" ", UTF8},
+		{"koi8-u", RUSSIAN_KOI8_RU},
+		{"x-unknown", UNKNOWN_ENCODING},
+		{"", UNKNOWN_ENCODING},
+	} {
+		if got := ParseEncoding(tc.charset); got != tc.want {
+			t.Errorf("%q: want %v, got %v", tc.charset, tc.want, got)
+		}
+	}
+	if s := JAPANESE_SHIFT_JIS.String(); s != "JAPANESE_SHIFT_JIS" {
+		t.Errorf("want 'JAPANESE_SHIFT_JIS', got %q", s)
+	}
+	if s := NUM_ENCODINGS.String(); s != "UNKNOWN_ENCODING" {
+		t.Errorf("want 'UNKNOWN_ENCODING', got %q", s)
+	}
+}
+
+func TestToUTF8(t *testing.T) {
+	for _, tc := range []struct {
+		enc        Encoding
+		data, want string
+	}{
+		{JAPANESE_SHIFT_JIS, "\x93\xfa\x96\x7b\x8c\xea\x82\xcc\x83\x65\x83\x4c\x83\x58\x83\x67\x81\x41\xb6\xc0\xb6\xc5", "日本語のテキスト、ｶﾀｶﾅ"},
+		{JAPANESE_EUC_JP, "\xc6\xfc\xcb\xdc\xb8\xec\xa4\xce\xa5\xc6\xa5\xad\xa5\xb9\xa5\xc8", "日本語のテキスト"},
+		{JAPANESE_JIS, "\x1b\x24\x42\x46\x7c\x4b\x5c\x38\x6c\x1b\x28\x42\x20\x74\x65\x78\x74\x20\x1b\x24\x42\x25\x46\x25\x2d\x25\x39\x25\x48\x1b\x28\x42", "日本語 text テキスト"},
+		{CHINESE_BIG5, "\xc1\x63\xc5\xe9\xa4\xa4\xa4\xe5\xb4\xfa\xb8\xd5", "繁體中文測試"},
+		{GB18030, "\xbc\xf2\xcc\xe5\xd6\xd0\xce\xc4\xb2\xe2\xca\xd4\xa2\xe3\x94\x39\xfc\x36", "简体中文测试€😀"},
+		{KOREAN_EUC_KR, "\xc7\xd1\xb1\xb9\xbe\xee\x20\xc5\xd8\xbd\xba\xc6\xae", "한국어 텍스트"},
+		{RUSSIAN_CP1251, "\xcf\xf0\xe8\xe2\xe5\xf2\x2c\x20\xec\xe8\xf0", "Привет, мир"},
+		{RUSSIAN_KOI8_R, "\xf0\xd2\xc9\xd7\xc5\xd4\x2c\x20\xcd\xc9\xd2", "Привет, мир"},
+		{ISO_8859_7, "\xca\xe1\xeb\xe7\xec\xdd\xf1\xe1", "Καλημέρα"},
+		{MSFT_CP1252, "\x63\x61\x66\xe9\x20\x93\x71\x75\x6f\x74\x65\x64\x94\x20\x80", "café “quoted” €"},
+		{ISO_8859_1, "caf\xe9", "café"},
+		{HZ_GB_2312, "\x7e\x7b\x3c\x72\x4c\x65\x7e\x7d\x7e\x7e\x7e\x7b\x56\x50\x4e\x44\x7e\x7d", "简体~中文"},
+		{UTF16LE, "\xff\xfe\x68\x00\xe9\x00\x6c\x00\x6c\x00\x6f\x00\x20\x00\x3d\xd8\x00\xde", "héllo 😀"},
+		{UTF16BE, "\x00\x68\x00\xe9\x00\x6c\x00\x6c\x00\x6f\x00\x20\xd8\x3d\xde\x00", "héllo 😀"},
+		{UTF16LE, "\xfe\xff\x00\x68\x00\xe9", "hé"}, // "utf-16" with a big-endian mark
+		{UTF16BE, "\xff\xfe\x68\x00\xe9\x00", "hé"},
+		{UTF32BE, "\x00\x00\x00\x68\x00\x00\x00\xe9\x00\x00\x00\x6c\x00\x00\x00\x6c\x00\x00\x00\x6f\x00\x00\x00\x20\x00\x01\xf6\x00", "héllo 😀"},
+		{UTF8, "héllo", "héllo"},
+
+		// Malformed input
+		{JAPANESE_SHIFT_JIS, "\x93\x7f\xff", "�\x7f�"},
+		{KOREAN_EUC_KR, "\xc7", "�"},
+		{GB18030, "\x81\x30", "�0"},
+	} {
+		got, err := toUTF8([]byte(tc.data), tc.enc)
+		if err != nil {
+			t.Errorf("%v: %v", tc.enc, err)
+			continue
+		}
+		if string(got) != tc.want {
+			t.Errorf("%v: want %q, got %q", tc.enc, tc.want, got)
+		}
+	}
+
+	if _, err := toUTF8([]byte("text"), TSCII); err == nil {
+		t.Error("want error for TSCII")
+	}
+}
+
+func TestTrimPartialChar(t *testing.T) {
+	for _, tc := range []struct {
+		enc        Encoding
+		data, want string
+	}{
+		{UTF8, "ab\xc3", "ab"},
+		{UTF8, "a\xf0\x9f\x98", "a"},
+		{UTF8, "ab\xc3\xa4", "ab\xc3\xa4"},
+		{UTF16LE, "\x68\x00\xe9", "\x68\x00"},
+		{UTF16LE, "\x68\x00\x3d\xd8", "\x68\x00"},
+		{UTF16LE, "\xfe\xff\x00\x68\xd8\x3d", "\xfe\xff\x00\x68"},
+		{UTF16BE, "\x00\x68\xd8\x3d\xde\x00", "\x00\x68\xd8\x3d\xde\x00"},
+		{UTF32LE, "\x68\x00\x00\x00\x68\x00", "\x68\x00\x00\x00"},
+		{JAPANESE_SHIFT_JIS, "\x93\xfa\x96\x7b\x8c", "\x93\xfa\x96\x7b"},
+		// A trail byte that looks like a lead byte
+		{JAPANESE_SHIFT_JIS, "\x83\x8c", "\x83\x8c"},
+		{JAPANESE_SHIFT_JIS, "\xb6\xc0", "\xb6\xc0"},
+		{JAPANESE_EUC_JP, "\xc6\xfc\x8f\xb0", "\xc6\xfc"},
+		{KOREAN_EUC_KR, "\xc7\xd1\xb1", "\xc7\xd1"},
+		{CHINESE_BIG5, "\xc1\x63\xc5", "\xc1\x63"},
+		{GB18030, "\xbc\xf2\x94\x39", "\xbc\xf2"},
+		{GB18030, "\xbc\xf2\x94\x39\xfc\x36", "\xbc\xf2\x94\x39\xfc\x36"},
+		{MSFT_CP1252, "caf\xe9", "caf\xe9"},
+		{JAPANESE_JIS, "\x1b\x24", "\x1b\x24"},
+	} {
+		if got := trimPartialChar([]byte(tc.data), tc.enc); string(got) != tc.want {
+			t.Errorf("%v %q: want %q, got %q", tc.enc, tc.data, tc.want, got)
+		}
+	}
+}
@@ -1,13 +1,55 @@
 // +build cgo 
 
 #include <cstddef>
+#include <stdlib.h>
 #include <string.h>
 #include <stdio.h>
 #include <string>
+#include <vector>
 
 #include "compact_lang_det.h"
+#include "compact_lang_det_hint_code.h"
+#include "compact_lang_det_impl.h"
+#include "encodings.h"
+#include "getonescriptspan.h"
 #include "cld2.h"
 
+namespace CLD2 {
+extern const int kAvgDeltaOctaScoreSize;
+extern const short kAvgDeltaOctaScore[];
+}
+
+// CopyExtras copies the reliability, close-set merges and applied priors
+// to dst.
+static void CopyExtras(result *dst, const CLD2::SummaryExtras &extras) {
+    memcpy(&dst->reliability[0], &extras.reliable_percent3[0], sizeof(dst->reliability));
+    int n = extras.closepairs.size();
+    if (n > MAX_CLOSEPAIRS) {
+        n = MAX_CLOSEPAIRS;
+    }
+    for (int i = 0; i < n; i++) {
+        const CLD2::ClosePairMerge &m = extras.closepairs[i];
+        dst->closepairs[i].from_language = m.from_lang;
+        dst->closepairs[i].to_language = m.to_lang;
+        dst->closepairs[i].from_bytes = m.from_bytes;
+        dst->closepairs[i].to_bytes = m.to_bytes;
+        dst->closepairs[i].from_score = m.from_score;
+        dst->closepairs[i].to_score = m.to_score;
+    }
+    dst->num_closepairs = n;
+    n = extras.priors.size();
+    if (n > MAX_APPLIED_PRIORS) {
+        n = MAX_APPLIED_PRIORS;
+    }
+    for (int i = 0; i < n; i++) {
+        const CLD2::AppliedLangPrior &a = extras.priors[i];
+        dst->priors[i].language = CLD2::GetCLDPriorLang(a.prior);
+        dst->priors[i].weight = CLD2::GetCLDPriorWeight(a.prior);
+        dst->priors[i].sources = a.sources;
+    }
+    dst->num_priors = n;
+}
+
 const char* DetectLang(char *data, int length) {
 
     bool is_plain_text = true;
@@ -86,6 +128,7 @@ void DetectThree(result *dst, char *data, int length) {
     CLD2::ResultChunkVector resultchunkvector;
     int text_bytes;
     bool is_reliable;
+    CLD2::SummaryExtras extras;
 
     if (length <= 0) {
         length = strlen(data);
@@ -93,22 +136,236 @@ void DetectThree(result *dst, char *data, int length) {
 
     CLD2::Language summary_lang = CLD2::UNKNOWN_LANGUAGE;
 
-    summary_lang = CLD2::ExtDetectLanguageSummary(data, 
+    // As ExtDetectLanguageSummary, also returning reliability
+    summary_lang = CLD2::DetectLanguageSummaryV2(data,
             length,
             is_plain_text,
             &cldhints,
+            allow_extended_lang,
             flags,
+            CLD2::UNKNOWN_LANGUAGE,
             language3,
             percent3,
             normalized_score3,
             &resultchunkvector,
             &text_bytes,
-            &is_reliable);
+            &is_reliable,
+            &extras);
+    CopyExtras(dst, extras);
+
+    memcpy(&dst->language[0], &language3[0], sizeof(language3));
+    memcpy(&dst->percent[0], &percent3[0], sizeof(percent3));
+    memcpy(&dst->normalized_score[0], &normalized_score3[0], sizeof(normalized_score3));
+    dst->reliable = char(is_reliable);
+    dst->text_bytes = text_bytes;
+    return;
+}
+
+void DetectSummary(result *dst, char *data, int length, char is_plain_text, hints *h, chunk **chunks, int *num_chunks) {
+    CLD2::CLDHints cldhints = {NULL, NULL, CLD2::UNKNOWN_ENCODING, CLD2::UNKNOWN_LANGUAGE};
+    int flags = 0;
+    CLD2::Language language3[3];
+    int percent3[3];
+    double normalized_score3[3];
+    CLD2::ResultChunkVector resultchunkvector;
+    int text_bytes;
+    bool is_reliable;
+    CLD2::SummaryExtras extras;
+
+    if (h != NULL) {
+        cldhints.content_language_hint = h->content_language;
+        cldhints.tld_hint = h->tld;
+        cldhints.encoding_hint = h->encoding;
+        cldhints.language_hint = CLD2::Language(h->language);
+        cldhints.lang_priors = h->priors;
+        cldhints.num_lang_priors = h->num_priors;
+        if (h->no_lang_tags) {
+            flags |= CLD2::kCLDFlagNoLangTags;
+        }
+    }
+
+    // As ExtDetectLanguageSummary, also returning reliability
+    CLD2::DetectLanguageSummaryV2(data,
+            length,
+            is_plain_text != 0,
+            &cldhints,
+            true,
+            flags,
+            CLD2::UNKNOWN_LANGUAGE,
+            language3,
+            percent3,
+            normalized_score3,
+            &resultchunkvector,
+            &text_bytes,
+            &is_reliable,
+            &extras);
+    CopyExtras(dst, extras);
 
     memcpy(&dst->language[0], &language3[0], sizeof(language3));
     memcpy(&dst->percent[0], &percent3[0], sizeof(percent3));
     memcpy(&dst->normalized_score[0], &normalized_score3[0], sizeof(normalized_score3));
     dst->reliable = char(is_reliable);
     dst->text_bytes = text_bytes;
+
+    // Per-span languages, if asked for; the caller frees them
+    if (chunks != NULL) {
+        *num_chunks = resultchunkvector.size();
+        *chunks = (chunk *)malloc((resultchunkvector.size() + 1) * sizeof(chunk));
+        int k = 0;
+        for (int i = 0; i < *num_chunks; i++) {
+            const CLD2::ResultChunk &rc = resultchunkvector[i];
+            (*chunks)[i].offset = rc.offset;
+            (*chunks)[i].bytes = rc.bytes;
+            (*chunks)[i].language = rc.lang1;
+
+            // The chunks scored that start in this one
+            int bytes = 0, delta = 0, score = 0;
+            while (k < int(extras.chunks.size()) && extras.chunks[k].offset < rc.offset + rc.bytes) {
+                const CLD2::ChunkReliability &cr = extras.chunks[k++];
+                if (cr.offset < rc.offset) {
+                    continue;
+                }
+                bytes += cr.bytes;
+                delta += cr.reliability_delta * cr.bytes;
+                score += cr.reliability_score * cr.bytes;
+            }
+            (*chunks)[i].reliability_delta = delta / (bytes ? bytes : 1);
+            (*chunks)[i].reliability_score = score / (bytes ? bytes : 1);
+        }
+    }
     return;
 }
+
+// ExtractText copies the text CLD2 sees in data to dst->text, and the
+// offset in data of each text byte to dst->offsets, followed by the
+// offset of the end. Both are allocated with malloc. With letters set,
+// the text is the lowercased letters that are scored; otherwise it is
+// all non-tag text with entities expanded.
+void ExtractText(extracted *dst, char *data, int length, char is_plain_text, char letters) {
+    CLD2::ScriptScanner ss(data, length, is_plain_text != 0, letters == 0, letters == 0);
+    CLD2::LangSpan span;
+    std::string text;
+    std::vector<int> offsets;
+
+    for (;;) {
+        bool ok;
+        if (letters != 0) {
+            ok = ss.GetOneScriptSpanLower(&span);
+        } else {
+            ok = ss.GetOneTextSpan(&span);
+        }
+        if (!ok) {
+            break;
+        }
+        text.append(span.text, span.text_bytes);
+        for (int i = 0; i < span.text_bytes; i++) {
+            offsets.push_back(ss.MapBack(i));
+        }
+    }
+    offsets.push_back(length);
+
+    dst->text_bytes = text.size();
+    dst->text = (char *)malloc(text.size() + 1);
+    memcpy(dst->text, text.data(), text.size());
+    dst->text[text.size()] = '\0';
+    dst->offsets = (int *)malloc(offsets.size() * sizeof(int));
+    memcpy(dst->offsets, &offsets[0], offsets.size() * sizeof(int));
+    return;
+}
+
+// ExpectedScore returns the average score per 1024 bytes of real text in
+// language, as used for reliability_score. script4 selects the column:
+// 0 Latin, 1 Cyrillic, 2 Arabic, 3 other. If the language has no score
+// for that script, the largest of its scores is returned.
+int ExpectedScore(int language, int script4) {
+    if (language < 0 || (language + 1) * 4 > CLD2::kAvgDeltaOctaScoreSize) {
+        return 0;
+    }
+    const short *scores = &CLD2::kAvgDeltaOctaScore[language * 4];
+    if (script4 >= 0 && script4 < 4 && scores[script4] != 0) {
+        return scores[script4];
+    }
+    int best = 0;
+    for (int i = 0; i < 4; i++) {
+        if (scores[i] > best) {
+            best = scores[i];
+        }
+    }
+    return best;
+}
+
+// CopyPriors copies the packed priors in lps to priors, which has room
+// for MAX_PRIORS, and returns how many there are.
+static int CopyPriors(short *priors, const CLD2::CLDLangPriors &lps) {
+    int n = CLD2::GetCLDLangPriorCount(const_cast<CLD2::CLDLangPriors *>(&lps));
+    if (n > MAX_PRIORS) {
+        n = MAX_PRIORS;
+    }
+    memcpy(priors, &lps.prior[0], n * sizeof(short));
+    return n;
+}
+
+// TLDPriors looks up the priors CLD2 gives to a top-level domain hint,
+// as SetCLDTLDHint, and returns how many it stored in priors.
+int TLDPriors(const char *tld, short *priors) {
+    CLD2::CLDLangPriors lps;
+    CLD2::InitCLDLangPriors(&lps);
+    CLD2::SetCLDTLDHint(tld, &lps);
+    return CopyPriors(priors, lps);
+}
+
+// LangTagPriors looks up the priors CLD2 gives to a lowercased,
+// comma-separated list of language tags, as SetCLDLangTagsHint, and
+// returns how many it stored in priors.
+int LangTagPriors(const char *langtags, short *priors) {
+    CLD2::CLDLangPriors lps;
+    CLD2::InitCLDLangPriors(&lps);
+    CLD2::SetCLDLangTagsHint(langtags, &lps);
+    return CopyPriors(priors, lps);
+}
+
+// DeclaredLanguages finds the languages declared by lang attributes and
+// language meta tags in the first max_scan bytes of HTML data, as CLD2
+// does for hints, and stores at most max_languages of them in languages
+// in the order declared. It returns how many it stored. Like
+// SetCLDLangTagsHint, it ignores more than five tags.
+int DeclaredLanguages(char *data, int length, int max_scan, int *languages, int max_languages) {
+    std::string tags = CLD2::GetLangTagsFromHtml(data, length, max_scan);
+    std::vector<std::string> list;
+    size_t pos = 0;
+    while (pos < tags.size()) {
+        size_t comma = tags.find(',', pos);
+        if (comma == std::string::npos) {
+            comma = tags.size();
+        }
+        if (comma > pos) {
+            list.push_back(tags.substr(pos, comma - pos));
+        }
+        pos = comma + 1;
+    }
+    if (list.size() > 5) {
+        return 0;
+    }
+
+    int n = 0;
+    for (size_t i = 0; i < list.size(); i++) {
+        CLD2::CLDLangPriors lps;
+        CLD2::InitCLDLangPriors(&lps);
+        CLD2::SetCLDLangTagsHint(list[i], &lps);
+        for (int j = 0; j < lps.n; j++) {
+            // Negative priors are languages the tag rules out
+            if (CLD2::GetCLDPriorWeight(lps.prior[j]) <= 0) {
+                continue;
+            }
+            int lang = CLD2::GetCLDPriorLang(lps.prior[j]);
+            bool seen = false;
+            for (int k = 0; k < n; k++) {
+                seen = seen || languages[k] == lang;
+            }
+            if (!seen && n < max_languages) {
+                languages[n++] = lang;
+            }
+        }
+    }
+    return n;
+}
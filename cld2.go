@@ -41,11 +41,281 @@ func DetectLang(text string) Language {
 // DetectThree returns up to three language guesses.
 // Extended languages are enabled.
 // Unknown languages are removed from the resultset.
+// Estimates have no Confidence; use DetectWithOptions for it.
 func DetectThree(text string) Languages {
 	cs := C.CString(text)
 	dst := new(C.struct__result)
 	C.DetectThree(dst, cs, -1)
 	C.free(unsafe.Pointer(cs))
+	return newLanguages(dst)
+}
+
+// DetectEncoded returns up to three language guesses for data
+// in the given encoding. The data is converted to UTF-8 first,
+// and the encoding is passed on to CLD2 as a hint. A character
+// cut off at the end of data, as when it is the start of a longer
+// document, is left out.
+// An error is returned if the encoding can't be converted.
+func DetectEncoded(data []byte, enc Encoding) (Languages, error) {
+	return DetectEncodedWithOptions(data, enc, Options{})
+}
+
+// DetectEncodedWithOptions is DetectEncoded with options for detecting
+// the converted text. The encoding is added to opts.Hints, or to NoHints
+// if there are none. Span offsets are in the converted text.
+func DetectEncodedWithOptions(data []byte, enc Encoding, opts Options) (Languages, error) {
+	text, err := toUTF8(trimPartialChar(data, enc), enc)
+	if err != nil {
+		return Languages{}, err
+	}
+	hints := NoHints
+	if opts.Hints != nil {
+		hints = *opts.Hints
+	}
+	hints.Encoding = enc
+	opts.Hints = &hints
+	return DetectWithOptions(text, opts)
+}
+
+// DetectWithOptions returns up to three language guesses for text,
+// which is checked and repaired as UTF-8 first. The repairs are
+// reported in the UTF8 field of the result.
+// An error is returned only in UTF8Reject mode.
+func DetectWithOptions(text []byte, opts Options) (Languages, error) {
+	input := text
+	var repairs OffsetMap
+	text, rep, err := repairUTF8(text, opts.UTF8, &repairs)
+	if err != nil {
+		return Languages{UTF8: rep}, err
+	}
+	res := detectRepaired(input, text, &repairs, opts)
+	res.UTF8 = rep
+	if opts.WithoutHints {
+		// The same detection with only the hints left out
+		base := opts
+		base.Hints, base.WithoutHints, base.noLangTags = nil, false, true
+		without := detectRepaired(input, text, &repairs, base)
+		without.UTF8 = rep
+		res.WithoutHints = &without
+	}
+	return res, nil
+}
+
+// detectRepaired detects text, which repairUTF8 made from input with
+// repairs, and maps span offsets to the coordinates of opts.
+func detectRepaired(input, text []byte, repairs *OffsetMap, opts Options) Languages {
+	res := detect(text, opts)
+	if !opts.Spans {
+		return res
+	}
+	// The text the span offsets refer to
+	coords := text
+	switch opts.Offsets {
+	case InputCoordinates:
+		res.Spans = mapSpans(res.Spans, repairs.MapBack)
+		coords = input
+	case TextCoordinates:
+		if opts.HTML {
+			var m OffsetMap
+			coords, m = extract(text, false, false)
+			res.Spans = mapSpans(res.Spans, m.MapForward)
+		}
+	case LetterCoordinates:
+		var m OffsetMap
+		coords, m = extract(text, !opts.HTML, true)
+		res.Spans = mapSpans(res.Spans, m.MapForward)
+	}
+	if opts.CharOffsets {
+		addCharOffsets(res.Spans, coords)
+	}
+	return res
+}
+
+// Quality rates how much text looks like real language, from 0 for
+// junk to 1 for normal text, and labels what lowered the rating. It
+// combines the reliability CLD2 scores each span of text with, from
+// its normalized score and its margin over other languages, with the
+// repetition and space-density heuristics CLD2 uses to squeeze text.
+func Quality(text string) TextQuality {
+	repaired, _, _ := RepairUTF8([]byte(text), UTF8Replace)
+	res, _, spans := detectFeatures(repaired, Options{Spans: true})
+	letters, _ := extract(repaired, true, true)
+	return assessQuality(res, letters, spans)
+}
+
+// FitCalibration fits a Calibration for Estimate.Confidence to texts of
+// known language, detected with opts. Every estimate is one observation,
+// right if it is the labeled language. Fit on a few hundred texts or
+// more per script, of the kinds and lengths that will be detected later.
+// Texts rejected under opts.UTF8 are skipped.
+func FitCalibration(texts []LabeledText, opts Options) *Calibration {
+	opts.Spans = false
+	var obs []calibrationObs
+	for _, t := range texts {
+		text, _, err := RepairUTF8(t.Text, opts.UTF8)
+		if err != nil {
+			continue
+		}
+		res, fs, _ := detectFeatures(text, opts)
+		for i, e := range res.Estimates {
+			obs = append(obs, calibrationObs{fs[i], e.Language == t.Language})
+		}
+	}
+	return fitCalibration(obs)
+}
+
+// PriorsForTLD returns the priors CLD2 gives a document under the
+// top-level domain tld, such as "fr" or "com", when it is passed as
+// Hints.TLD. It returns empty Priors for domains CLD2 knows nothing about.
+func PriorsForTLD(tld string) *Priors {
+	cs := C.CString(tld)
+	defer C.free(unsafe.Pointer(cs))
+	var priors [C.MAX_PRIORS]C.short
+	n := C.TLDPriors(cs, &priors[0])
+	return cPriors(priors[:n])
+}
+
+// PriorsForLangTag returns the priors CLD2 gives a document declared to
+// be in the language tag, such as "pt-BR" or "Deutsch", by a lang
+// attribute or Content-Language. The tag may be a comma-separated list
+// of up to five tags. It returns empty Priors for tags CLD2 doesn't know.
+func PriorsForLangTag(tag string) *Priors {
+	cs := C.CString(normalizeLangTags(tag))
+	defer C.free(unsafe.Pointer(cs))
+	var priors [C.MAX_PRIORS]C.short
+	n := C.LangTagPriors(cs, &priors[0])
+	return cPriors(priors[:n])
+}
+
+// detect runs CLD2 over text, which must be valid UTF-8.
+// Span offsets are left in bytes of text.
+func detect(text []byte, opts Options) Languages {
+	res, fs, _ := detectFeatures(text, opts)
+	c := opts.Calibration
+	if c == nil {
+		c = defaultCalibration
+	}
+	if c != nil {
+		c.apply(res, fs)
+	}
+	if p := opts.Reliability; p != nil {
+		res.Unreliable = p.check(res, fs, res.Reliable)
+		res.Reliable = res.Unreliable == ""
+	}
+	if opts.CloseSets {
+		collapseCloseSets(&res)
+	}
+	return res
+}
+
+// detectFeatures is detect without calibration, returning the
+// calibration features of each estimate instead, and with opts.Spans
+// the reliability of each span as CLD2 returned it.
+func detectFeatures(text []byte, opts Options) (Languages, []calibrationFeatures, []spanReliability) {
+	cs := cBytes(text)
+	defer C.free(unsafe.Pointer(cs))
+
+	var h *C.struct__hints
+	hints := opts.Hints
+	if hints == nil && opts.noLangTags {
+		hints = &NoHints
+	}
+	if hints != nil {
+		h = new(C.struct__hints)
+		h.encoding = C.int(hints.Encoding)
+		h.language = C.int(hints.Language)
+		if hints.ContentLanguage != "" {
+			h.content_language = C.CString(hints.ContentLanguage)
+			defer C.free(unsafe.Pointer(h.content_language))
+		}
+		if hints.TLD != "" {
+			h.tld = C.CString(hints.TLD)
+			defer C.free(unsafe.Pointer(h.tld))
+		}
+		if hints.Priors != nil {
+			if packed := hints.Priors.packed(); len(packed) > 0 {
+				h.priors = (*C.short)(C.malloc(C.size_t(len(packed)) * C.sizeof_short))
+				defer C.free(unsafe.Pointer(h.priors))
+				copy(unsafe.Slice((*int16)(unsafe.Pointer(h.priors)), len(packed)), packed)
+				h.num_priors = C.int(len(packed))
+			}
+		}
+		if opts.noLangTags {
+			h.no_lang_tags = 1
+		}
+	}
+	var isPlain C.char
+	if !opts.HTML {
+		isPlain = 1
+	}
+	var chunks **C.struct__chunk
+	var c *C.struct__chunk
+	var n C.int
+	if opts.Spans {
+		chunks = &c
+	}
+	dst := new(C.struct__result)
+	C.DetectSummary(dst, cs, C.int(len(text)), isPlain, h, chunks, &n)
+	res := newLanguages(dst)
+	var rel []spanReliability
+	if c != nil {
+		defer C.free(unsafe.Pointer(c))
+		res.Spans = make([]Span, 0, int(n))
+		rel = make([]spanReliability, 0, int(n))
+		for _, ch := range unsafe.Slice(c, int(n)) {
+			res.Spans = appendSpan(res.Spans, Span{
+				Offset:   int(ch.offset),
+				Length:   int(ch.bytes),
+				Language: Language(ch.language),
+			})
+			rel = append(rel, spanReliability{
+				bytes: int(ch.bytes),
+				delta: int(ch.reliability_delta),
+				score: int(ch.reliability_score),
+			})
+		}
+	}
+	return res, resultFeatures(res, dst, text, opts.HTML), rel
+}
+
+// resultFeatures returns the calibration features of each estimate in
+// res, which newLanguages made from dst.
+func resultFeatures(res Languages, dst *C.struct__result, text []byte, html bool) []calibrationFeatures {
+	if html {
+		text, _ = extract(text, false, false)
+	}
+	col := scriptColumn(text)
+	reliability := make([]int, 0, len(res.Estimates))
+	for i := range dst.language {
+		if Language(dst.language[i]) != UNKNOWN_LANGUAGE {
+			reliability = append(reliability, int(dst.reliability[i]))
+		}
+	}
+	return estimateFeatures(res, reliability, expectedScores(res, col), col)
+}
+
+// expectedScores returns the average score of real text in the language
+// of each estimate, in script column col.
+func expectedScores(res Languages, col int) []int {
+	expected := make([]int, len(res.Estimates))
+	for i, e := range res.Estimates {
+		expected[i] = int(C.ExpectedScore(C.int(e.Language), C.int(col)))
+	}
+	return expected
+}
+
+// cBytes copies b to a NUL-terminated C buffer,
+// which must be freed by the caller.
+func cBytes(b []byte) *C.char {
+	p := C.malloc(C.size_t(len(b) + 1))
+	buf := unsafe.Slice((*byte)(p), len(b)+1)
+	copy(buf, b)
+	buf[len(b)] = 0
+	return (*C.char)(p)
+}
+
+// newLanguages converts a C result, leaving out unknown languages.
+func newLanguages(dst *C.struct__result) Languages {
 	res := make([]Estimate, 0, 3)
 	for i := range dst.language {
 		var est Estimate
@@ -61,5 +331,35 @@ func DetectThree(text string) Languages {
 	if dst.reliable == 0 {
 		rel = false
 	}
-	return Languages{Estimates: res, Reliable: rel, TextBytes: int(dst.text_bytes)}
+	var why ReliabilityCriterion
+	if !rel {
+		why = CriterionCLD2
+	}
+	var alts []Alternative
+	for _, p := range dst.closepairs[:dst.num_closepairs] {
+		alts = append(alts, Alternative{
+			Language: Language(p.from_language),
+			Chosen:   Language(p.to_language),
+			Bytes:    int(p.from_bytes),
+			Share:    scoreShare(int(p.from_score), int(p.to_score)),
+		})
+	}
+	var priors []AppliedPrior
+	for _, p := range dst.priors[:dst.num_priors] {
+		priors = append(priors, AppliedPrior{
+			Language: Language(p.language),
+			Weight:   int(p.weight),
+			Sources:  priorSources(int(p.sources)),
+		})
+	}
+	return Languages{Estimates: res, Reliable: rel, Unreliable: why, Ambiguity: alts, Priors: priors, TextBytes: int(dst.text_bytes)}
+}
+
+// cPriors converts packed C priors.
+func cPriors(packed []C.short) *Priors {
+	out := make([]int16, len(packed))
+	for i, v := range packed {
+		out[i] = int16(v)
+	}
+	return unpackPriors(out)
 }
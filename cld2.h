@@ -4,18 +4,81 @@
 extern "C" {
 #endif
 
+// A close-set language merged into another, as CLD2::ClosePairMerge
+typedef struct _closepair {
+   int from_language;
+   int to_language;
+   int from_bytes;
+   int to_bytes;
+   int from_score;
+   int to_score;
+} closepair;
+
+// At most 15 merges: one less than the size of each close set
+#define MAX_CLOSEPAIRS 16
+
+// A language prior CLD2 applied, with kPriorSource bits for its sources
+typedef struct _appliedprior {
+   int language;
+   int weight;
+   int sources;
+} appliedprior;
+
+// CLD2 keeps at most four priors
+#define MAX_APPLIED_PRIORS 4
+
 typedef struct _result {
    int language[3];
    int percent[3];
    double normalized_score[3];
+   int reliability[3];
+   closepair closepairs[MAX_CLOSEPAIRS];
+   int num_closepairs;
+   appliedprior priors[MAX_APPLIED_PRIORS];
+   int num_priors;
    int text_bytes;
    char reliable;
 } result;
 
+typedef struct _hints {
+   const char *content_language;
+   const char *tld;
+   int encoding;
+   int language;
+   short *priors;
+   int num_priors;
+   char no_lang_tags;   // ignore lang attributes in HTML
+} hints;
+
+// At most kMaxOneCLDLangPrior priors from one lookup
+#define MAX_PRIORS 14
+
+// A span of one language, with the reliability 0..100 CLD2 scored its
+// text with, weighted by bytes
+typedef struct _chunk {
+   int offset;
+   int bytes;
+   int language;
+   int reliability_delta;
+   int reliability_score;
+} chunk;
+
+typedef struct _extracted {
+   char *text;
+   int *offsets;
+   int text_bytes;
+} extracted;
+
 
 const char* DetectLang(char *data, int length);
 int DetectLangCode(char *data, int length);
 void DetectThree(result *dst, char *data, int length);
+void DetectSummary(result *dst, char *data, int length, char is_plain_text, hints *h, chunk **chunks, int *num_chunks);
+void ExtractText(extracted *dst, char *data, int length, char is_plain_text, char letters);
+int ExpectedScore(int language, int script4);
+int TLDPriors(const char *tld, short *priors);
+int DeclaredLanguages(char *data, int length, int max_scan, int *languages, int max_languages);
+int LangTagPriors(const char *langtags, short *priors);
 
 #ifdef __cplusplus
 }
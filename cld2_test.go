@@ -3,7 +3,15 @@
 package cld2
 
 import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"strings"
 	"testing"
+	"unicode/utf16"
+	"unicode/utf8"
 )
 
 var dkText = `Omkring 4.000 personer har gennem de seneste år forladt EU-landene for at deltage i krigen i Irak og Syrien.
@@ -73,6 +81,31 @@ func TestDetectThree(t *testing.T) {
 	}
 }
 
+func TestDetectEncoded(t *testing.T) {
+	for _, tc := range []struct {
+		enc  Encoding
+		data string
+		want Language
+	}{
+		{RUSSIAN_CP1251, "\xc1\xfb\xf1\xf2\xf0\xe0\xff\x20\xea\xee\xf0\xe8\xf7\xed\xe5\xe2\xe0\xff\x20\xeb\xe8\xf1\xe0\x20\xef\xf0\xfb\xe3\xe0\xe5\xf2\x20\xf7\xe5\xf0\xe5\xe7\x20\xeb\xe5\xed\xe8\xe2\xf3\xfe\x20\xf1\xee\xe1\xe0\xea\xf3\x2c\x20\xf7\xf2\xee\xe1\xfb\x20\xef\xf0\xee\xe2\xe5\xf0\xe8\xf2\xfc\x20\xee\xef\xf0\xe5\xe4\xe5\xeb\xe5\xed\xe8\xe5\x20\xff\xe7\xfb\xea\xe0\x2e", RUSSIAN},
+		{JAPANESE_SHIFT_JIS, "\x91\xac\x82\xa2\x92\x83\x90\x46\x82\xcc\x83\x4c\x83\x63\x83\x6c\x82\xcd\x81\x41\x91\xd3\x82\xaf\x8e\xd2\x82\xcc\x8c\xa2\x82\xf0\x94\xf2\x82\xd1\x89\x7a\x82\xa6\x82\xdc\x82\xb5\x82\xbd", JAPANESE},
+		{UTF8, dkText, DANISH},
+	} {
+		res, err := DetectEncoded([]byte(tc.data), tc.enc)
+		if err != nil {
+			t.Errorf("%v: %v", tc.enc, err)
+			continue
+		}
+		if len(res.Estimates) == 0 || res.Estimates[0].Language != tc.want {
+			t.Errorf("%v: want %v, got %+v", tc.enc, tc.want, res)
+		}
+	}
+
+	if _, err := DetectEncoded([]byte("text"), TSCII); err == nil {
+		t.Error("want error for unsupported encoding")
+	}
+}
+
 type TestData struct {
 	ExpectLanguageCode string
 	ExpectLanguageName string
@@ -167,3 +200,378 @@ func BenchmarkDetectThreeShort(b *testing.B) {
 		_ = DetectThree(shortText)
 	}
 }
+
+func TestDetectWithOptions(t *testing.T) {
+	res, err := DetectWithOptions([]byte(dkText+"\xff\xfe"), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Estimates) < 1 || res.Estimates[0].Language != DANISH {
+		t.Errorf("want DANISH, got %+v", res)
+	}
+	if res.UTF8.InvalidSequences != 2 {
+		t.Errorf("want 2 invalid sequences reported, got %+v", res.UTF8)
+	}
+
+	res, err = DetectWithOptions([]byte("<p>"+dkText+"\xed\xa0\x80</p>"), Options{HTML: true, UTF8: UTF8Reject})
+	if err != ErrInvalidUTF8 {
+		t.Errorf("want ErrInvalidUTF8, got %v", err)
+	}
+	if res.UTF8.Surrogates != 1 {
+		t.Errorf("want 1 surrogate reported, got %+v", res.UTF8)
+	}
+}
+
+func TestExtractText(t *testing.T) {
+	html := []byte(`<html><head><title>Hi</title><script>var x = "<b>";</script></head>` +
+		`<body><p>Caf&eacute; &amp; b&auml;r</p></body></html>`)
+	text, offsets := ExtractText(html)
+	if string(text) != "  Hi Café &bär " {
+		t.Errorf("want script removed and entities expanded, got %q", text)
+	}
+	i := strings.Index(string(text), "bär")
+	if got := offsets.MapBack(i); got != strings.Index(string(html), "b&auml;r") {
+		t.Errorf("want 'bär' to map back to 'b&auml;r', got offset %d", got)
+	}
+	if got := offsets.MapBack(len(text)); got != len(html) {
+		t.Errorf("want end of text to map to end of html, got %d", got)
+	}
+}
+
+func TestDetectSpans(t *testing.T) {
+	html := []byte("<p>" + strings.Replace(dkText, "\n", "</p><p>", -1) + "</p>")
+	res, err := DetectWithOptions(html, Options{HTML: true, Spans: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Spans) == 0 {
+		t.Fatal("want spans")
+	}
+	end := 0
+	for _, s := range res.Spans {
+		if s.Offset < end || s.Offset+s.Length > len(html) {
+			t.Errorf("want ordered spans within the input, got %+v after %d", s, end)
+		}
+		end = s.Offset + s.Length
+	}
+	if res.Spans[0].Language != DANISH {
+		t.Errorf("want first span to be DANISH, got %+v", res.Spans[0])
+	}
+
+	text, _ := ExtractText(html)
+	res, err = DetectWithOptions(html, Options{HTML: true, Spans: true, Offsets: TextCoordinates})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range res.Spans {
+		if s.Offset < 0 || s.Offset+s.Length > len(text) {
+			t.Errorf("want span within extracted text of %d bytes, got %+v", len(text), s)
+		}
+		if s.Language == DANISH && strings.Contains(string(text[s.Offset:s.Offset+s.Length]), "<") {
+			t.Errorf("want no tags in text span %q", text[s.Offset:s.Offset+s.Length])
+		}
+	}
+}
+
+func TestDetectCharOffsets(t *testing.T) {
+	text := "😀 " + dkText
+	res, err := DetectWithOptions([]byte(text), Options{Spans: true, CharOffsets: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range res.Spans {
+		if n := utf8.RuneCountInString(text[:s.Offset]); n != s.RuneOffset {
+			t.Errorf("want rune offset %d, got %+v", n, s)
+		}
+		if n := len(utf16.Encode([]rune(text[:s.Offset]))); n != s.UTF16Offset {
+			t.Errorf("want UTF-16 offset %d, got %+v", n, s)
+		}
+	}
+}
+
+func TestQuality(t *testing.T) {
+	if q := Quality(dkText); q.Score < 0.5 {
+		t.Errorf("want good quality for dkText, got %+v", q)
+	}
+	if q := Quality(strings.Repeat("buy cheap pills now ", 50)); q.Score > 0.5 || len(q.Reasons) == 0 {
+		t.Errorf("want low quality for spam, got %+v", q)
+	}
+}
+
+func TestConfidence(t *testing.T) {
+	res, err := DetectWithOptions([]byte(dkText), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if defaultCalibration == nil && len(res.Estimates) > 0 && res.Estimates[0].Confidence != 0 {
+		t.Errorf("want no confidence without a calibration, got %+v", res.Estimates)
+	}
+
+	opts := Options{Calibration: &testCalibration}
+	res, err = DetectWithOptions([]byte(dkText), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Estimates) == 0 || res.Estimates[0].Confidence < 0.9 {
+		t.Errorf("want high confidence for dkText, got %+v", res.Estimates)
+	}
+	for _, e := range res.Estimates[1:] {
+		if e.Confidence >= res.Estimates[0].Confidence {
+			t.Errorf("want runner-up less confident than %+v, got %+v", res.Estimates[0], e)
+		}
+	}
+	short, err := DetectWithOptions([]byte("hej med dig"), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(short.Estimates) > 0 && short.Estimates[0].Confidence >= res.Estimates[0].Confidence {
+		t.Errorf("want short text less confident, got %+v", short.Estimates)
+	}
+}
+
+var genCalibration = flag.Bool("gen-calibration", false, "refit calibration_table.go on unittest_data.h")
+
+// TestGenerateCalibration fits defaultCalibration on the samples in
+// unittest_data.h and rewrites calibration_table.go. Run it with
+// "go generate".
+func TestGenerateCalibration(t *testing.T) {
+	if !*genCalibration {
+		t.Skip("run with -gen-calibration to refit calibration_table.go")
+	}
+	src, err := ioutil.ReadFile("unittest_data.h")
+	if err != nil {
+		t.Fatal(err)
+	}
+	samples := unittestSamples(src)
+	if len(samples) == 0 {
+		t.Fatal("no samples found in unittest_data.h")
+	}
+	c := FitCalibration(samples, Options{})
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by TestGenerateCalibration; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package cld2\n\n")
+	fmt.Fprintf(&buf, "// defaultCalibration is used when Options.Calibration is nil.\n")
+	fmt.Fprintf(&buf, "// It was fitted on %d samples from unittest_data.h and their prefixes.\n", len(samples))
+	fmt.Fprintf(&buf, "var defaultCalibration = %#v\n", c)
+	out, err := format.Source(bytes.ReplaceAll(buf.Bytes(), []byte("cld2."), nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile("calibration_table.go", out, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReliabilityOption(t *testing.T) {
+	res, err := DetectWithOptions([]byte(dkText), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Reliable || res.Unreliable != "" {
+		t.Errorf("want dkText reliable by CLD2's rule, got %+v", res)
+	}
+	strict := &ReliabilityPolicy{MinTextBytes: 10000, RequireCLD2: true}
+	res, err = DetectWithOptions([]byte(dkText), Options{Reliability: strict})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Reliable || res.Unreliable != CriterionTextBytes {
+		t.Errorf("want dkText too short for %+v, got %+v", strict, res)
+	}
+	lenient := &ReliabilityPolicy{MinPercent: 50}
+	res, err = DetectWithOptions([]byte("hej med dig"), Options{Reliability: lenient})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Estimates) > 0 && res.Estimates[0].Percent >= 50 && !res.Reliable {
+		t.Errorf("want short text reliable under %+v, got %+v", lenient, res)
+	}
+}
+
+func TestCloseSets(t *testing.T) {
+	text := "Hrvatski jezik je južnoslavenski jezik kojim govore Hrvati u Hrvatskoj, " +
+		"Bosni i Hercegovini i drugim zemljama. Službeni je jezik Republike Hrvatske."
+	res, err := DetectWithOptions([]byte(text), Options{CloseSets: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("%+v", res)
+	if len(res.Estimates) == 0 || res.Estimates[0].Group != "bs+hr+sr+sr-ME" {
+		t.Errorf("want the Serbo-Croatian group first, got %+v", res.Estimates)
+	}
+	for _, a := range res.Ambiguity {
+		if a.Language == a.Chosen || a.Language.Group() != a.Chosen.Group() {
+			t.Errorf("want alternative in the close set of the chosen language, got %+v", a)
+		}
+		if a.Share < 0 || a.Share > 1 {
+			t.Errorf("want share within 0..1, got %+v", a)
+		}
+	}
+}
+
+func TestPriorsHint(t *testing.T) {
+	hints := NoHints
+	hints.Priors = new(Priors).Add(DANISH, MinPriorWeight)
+	res, err := DetectWithOptions([]byte(dkText), Options{Hints: &hints})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Estimates) > 0 && res.Estimates[0].Language == DANISH {
+		t.Errorf("want Danish ruled out, got %+v", res.Estimates)
+	}
+
+	// The TLD boosts Danish, but the caller's negative weight wins.
+	hints.TLD = "dk"
+	hints.Priors = new(Priors).Add(DANISH, -4)
+	res, err = DetectWithOptions([]byte(dkText), Options{Hints: &hints})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Estimates) > 0 && res.Estimates[0].Language == DANISH {
+		t.Errorf("want Danish ruled out despite the TLD, got %+v", res.Estimates)
+	}
+	for _, p := range res.Priors {
+		if p.Language == DANISH && p.Weight != -4 {
+			t.Errorf("want Danish applied at -4, got %+v", p)
+		}
+	}
+
+	hints.TLD = ""
+	hints.Priors = new(Priors).Add(NORWEGIAN, 12)
+	res, err = DetectWithOptions([]byte("hej med dig"), Options{Hints: &hints})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Estimates) == 0 || res.Estimates[0].Language != NORWEGIAN {
+		t.Errorf("want Norwegian boosted, got %+v", res.Estimates)
+	}
+}
+
+func TestPriorsLookup(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		got  *Priors
+		want string
+	}{
+		{"tld ch", PriorsForTLD("ch"), "fr.4 de.4"},
+		{"tld DE", PriorsForTLD("DE"), "de.4"},
+		{"tld com", PriorsForTLD("com"), ""},
+		{"tag pt_BR", PriorsForLangTag("pt_BR"), "pt.10"},
+		{"tag Deutsch", PriorsForLangTag("Deutsch"), "de.10"},
+		{"tags", PriorsForLangTag("de, fr"), "fr.10 de.10"},
+		{"tag unknown", PriorsForLangTag("postscript"), ""},
+	} {
+		if got := DumpPriors(tc.got); got != tc.want {
+			t.Errorf("%s: want %q, got %q", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestContentLanguageRoundTrip(t *testing.T) {
+	// Every tag ContentLanguage writes must be one CLD2 itself reads back
+	// as the same language, such as "he" rather than "iw".
+	for l := Language(0); l < NUM_LANGUAGES; l++ {
+		tag := bcp47(l)
+		if tag == "" {
+			continue
+		}
+		list := PriorsForLangTag(tag).List()
+		if len(list) == 0 || list[0].Language != l {
+			t.Errorf("%v: tag %q gives priors %q", l, tag, DumpPriors(PriorsForLangTag(tag)))
+		}
+	}
+	for _, tag := range []string{"he", "jv", "zh-Hant"} {
+		if len(PriorsForLangTag(tag).List()) == 0 {
+			t.Errorf("tag %q gives no priors", tag)
+		}
+	}
+}
+
+func TestAppliedPriors(t *testing.T) {
+	hints := NoHints
+	hints.TLD = "no"
+	hints.Priors = new(Priors).Add(DANISH, -8)
+	html := `<html lang="nb"><body><p>hej med dig</p></body></html>`
+	res, err := DetectWithOptions([]byte(html), Options{HTML: true, Hints: &hints, Spans: true, WithoutHints: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sources := make(map[Language][]PriorSource)
+	for _, p := range res.Priors {
+		sources[p.Language] = p.Sources
+	}
+	if s := sources[NORWEGIAN]; len(s) != 2 || s[0] != SourceHTMLLang || s[1] != SourceTLD {
+		t.Errorf("want Norwegian from the lang attribute and the TLD, got %+v", res.Priors)
+	}
+	if s := sources[DANISH]; len(s) != 1 || s[0] != SourceExplicit {
+		t.Errorf("want Danish from explicit priors, got %+v", res.Priors)
+	}
+	if res.WithoutHints == nil {
+		t.Fatal("want a result without hints")
+	}
+	if len(res.WithoutHints.Priors) != 0 || res.WithoutHints.WithoutHints != nil {
+		t.Errorf("want no priors without hints, got %+v", res.WithoutHints)
+	}
+	if len(res.WithoutHints.Spans) == 0 {
+		t.Errorf("want spans without hints too, got %+v", res.WithoutHints)
+	}
+}
+
+func TestDeclaredLanguages(t *testing.T) {
+	for _, tc := range []struct {
+		html string
+		want []Language
+	}{
+		{`<html lang="de-AT"><body>Hallo</body></html>`, []Language{GERMAN}},
+		{`<html xml:lang="fr" lang="en-US"><meta http-equiv="content-language" content="nl">`, []Language{FRENCH, ENGLISH, DUTCH}},
+		{`<meta name="language" content="Deutsch">`, []Language{GERMAN}},
+		{`<html lang="no">`, []Language{NORWEGIAN}},
+		{`<font lang="postscript"><!-- lang=fr -->`, nil},
+		{`<a lang="de">de</a><span lang="fr">fr</span>`, []Language{FRENCH}},
+		{`<p lang="de,fr,it,es,nl,pt">`, nil},
+	} {
+		got := DeclaredLanguages([]byte(tc.html), 0)
+		if len(got) != len(tc.want) {
+			t.Errorf("%s: want %v, got %v", tc.html, tc.want, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != tc.want[i] {
+				t.Errorf("%s: want %v, got %v", tc.html, tc.want, got)
+				break
+			}
+		}
+	}
+
+	html := []byte(strings.Repeat(" ", 100) + `<html lang="it">`)
+	if got := DeclaredLanguages(html, 50); len(got) != 0 {
+		t.Errorf("want nothing past maxScan, got %v", got)
+	}
+}
+
+func TestAnnotateLangDetected(t *testing.T) {
+	html := []byte(`<html><body><p>` + dkText + `</p><p>` + shortText + ` ` + shortText + `</p></body></html>`)
+	res, err := DetectWithOptions(html, Options{HTML: true, Spans: true, Offsets: InputCoordinates})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(AnnotateLang(html, res, AnnotateOptions{}))
+	if !strings.HasPrefix(got, `<html lang="da">`) || !strings.Contains(got, `<p lang="de">Freuen`) {
+		t.Errorf("want Danish page with a German paragraph, got %s", got)
+	}
+}
+
+func TestContentLanguageHint(t *testing.T) {
+	res := Languages{Estimates: []Estimate{{Language: HEBREW, Percent: 50}, {Language: CHINESE_T, Percent: 30}, {Language: MONTENEGRIN, Percent: 20}}}
+	value := ContentLanguage(res, 0)
+	priors := make(map[Language]int)
+	for _, p := range PriorsForLangTag(value).List() {
+		priors[p.Language] = p.Weight
+	}
+	for _, e := range res.Estimates {
+		if priors[e.Language] <= 0 {
+			t.Errorf("want %v boosted by Content-Language %q, got %v", e.Language, value, priors)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package cld2
+
+import (
+	"math"
+	"strings"
+)
+
+// closeSets are the sets of statistically close languages of
+// LanguageCloseSet in lang_script.cc. When more than one language of a
+// set is scored, CLD2 gives all their text to the one with most bytes.
+var closeSets = [][]Language{
+	{INDONESIAN, MALAY},
+	{TIBETAN, DZONGKHA},
+	{CZECH, SLOVAK},
+	{ZULU, XHOSA},
+	{BOSNIAN, CROATIAN, SERBIAN, MONTENEGRIN},
+	{HINDI, MARATHI, BIHARI, NEPALI},
+	{NORWEGIAN, NORWEGIAN_N, DANISH},
+	{GALICIAN, SPANISH, PORTUGUESE},
+	{KINYARWANDA, RUNDI},
+}
+
+// CloseSet returns the set of statistically close languages l is in,
+// l included, or nil if l isn't in one.
+func (l Language) CloseSet() []Language {
+	for _, set := range closeSets {
+		for _, m := range set {
+			if m == l {
+				return set
+			}
+		}
+	}
+	return nil
+}
+
+// Group returns a label for the close set of l: the codes of its
+// languages joined by "+", such as "id+ms". For a language that isn't
+// in a close set it is the language code.
+func (l Language) Group() string {
+	set := l.CloseSet()
+	if set == nil {
+		return l.Code()
+	}
+	codes := make([]string, len(set))
+	for i, m := range set {
+		codes[i] = m.Code()
+	}
+	return strings.Join(codes, "+")
+}
+
+// Alternative is a language of a close set that CLD2 scored but then
+// merged into another language of the set.
+type Alternative struct {
+	Language Language `json:"code"`   // the language merged away
+	Chosen   Language `json:"chosen"` // the language its text was given to
+	Bytes    int      `json:"bytes"`  // text bytes scored as Language
+	// Share is the part of the two languages' total score that went to
+	// Language. At or near 0.5 the choice was a coin flip.
+	Share float64 `json:"share"`
+}
+
+// scoreShare returns a's share of a+b, or 0.5 if both are 0.
+func scoreShare(a, b int) float64 {
+	if a+b <= 0 {
+		return 0.5
+	}
+	return float64(a) / float64(a+b)
+}
+
+// collapseCloseSets sets the Group of each estimate in res and merges
+// estimates of the same group into the first of them.
+func collapseCloseSets(res *Languages) {
+	out := res.Estimates[:0]
+	seen := make(map[string]int)
+	for _, e := range res.Estimates {
+		e.Group = e.Language.Group()
+		i, ok := seen[e.Group]
+		if !ok {
+			seen[e.Group] = len(out)
+			out = append(out, e)
+			continue
+		}
+		out[i].Percent += e.Percent
+		// Estimates are of disjoint events
+		out[i].Confidence = math.Min(1, out[i].Confidence+e.Confidence)
+	}
+	res.Estimates = out
+}
@@ -0,0 +1,56 @@
+package cld2
+
+import "testing"
+
+func TestCloseSet(t *testing.T) {
+	for _, tc := range []struct {
+		lang  Language
+		group string
+	}{
+		{CROATIAN, "bs+hr+sr+sr-ME"},
+		{MALAY, "id+ms"},
+		{DANISH, "no+nn+da"},
+		{GALICIAN, "gl+es+pt"},
+		{GERMAN, "de"},
+	} {
+		if got := tc.lang.Group(); got != tc.group {
+			t.Errorf("%v: want group %q, got %q", tc.lang, tc.group, got)
+		}
+	}
+	if set := GERMAN.CloseSet(); set != nil {
+		t.Errorf("want no close set for German, got %v", set)
+	}
+	if set := SLOVAK.CloseSet(); len(set) != 2 || set[0] != CZECH {
+		t.Errorf("want Czech and Slovak, got %v", set)
+	}
+}
+
+func TestCollapseCloseSets(t *testing.T) {
+	res := Languages{Estimates: []Estimate{
+		{Language: CROATIAN, Percent: 50, Confidence: 0.5},
+		{Language: ENGLISH, Percent: 30, Confidence: 0.2},
+		{Language: SERBIAN, Percent: 20, Confidence: 0.25},
+	}}
+	collapseCloseSets(&res)
+	want := []Estimate{
+		{Language: CROATIAN, Percent: 70, Confidence: 0.75, Group: "bs+hr+sr+sr-ME"},
+		{Language: ENGLISH, Percent: 30, Confidence: 0.2, Group: "en"},
+	}
+	if len(res.Estimates) != len(want) {
+		t.Fatalf("want %+v, got %+v", want, res.Estimates)
+	}
+	for i := range want {
+		if res.Estimates[i] != want[i] {
+			t.Errorf("want %+v, got %+v", want[i], res.Estimates[i])
+		}
+	}
+}
+
+func TestScoreShare(t *testing.T) {
+	if got := scoreShare(300, 900); got != 0.25 {
+		t.Errorf("want 0.25, got %v", got)
+	}
+	if got := scoreShare(0, 0); got != 0.5 {
+		t.Errorf("want a coin flip without scores, got %v", got)
+	}
+}
@@ -0,0 +1,322 @@
+// Command cld2-lang-audit checks that the lang attributes of HTML pages
+// match the language of their text, as WCAG 3.1.1 and 3.1.2 require.
+//
+// Usage:
+//
+//	cld2-lang-audit [flags] path...
+//
+// Each path is an HTML file or a directory, which is walked for files
+// ending in .html, .htm or .xhtml. The text of each page is detected
+// once, without its lang attributes as hints so that they can't hide a
+// mismatch. Each element holding text, such as a paragraph or list item,
+// gets the language most of its text was detected in, which is compared
+// with the lang or xml:lang it declares or inherits. Elements no lang
+// attribute applies to are compared with the languages the page declares
+// as CLD2 reads them, which include content-language meta tags (see
+// cld2.DeclaredLanguages). Lang values are read the way CLD2 reads them
+// for hints, so "en-GB" and "Srpski" are understood. Elements whose text
+// is in another language, or that have no declared language at all, are
+// reported with the byte offset and length of the element.
+//
+// Languages match only if they are the same, have the same primary
+// code, such as zh and zh-Hant, or are Bosnian, Croatian, Serbian and
+// Montenegrin, which CLD2 can't tell apart. Spanish under lang="pt",
+// Slovak under lang="cs" and Danish under lang="no" are all reported.
+//
+// The flags are:
+//
+//	-json
+//		write one JSON object per finding instead of a line of text
+//	-min-bytes n
+//		ignore elements with fewer than n bytes of text in the detected
+//		language (default 40)
+//	-calibration file
+//		a calibration fitted with cld2.FitCalibration and saved as
+//		JSON, for the confidence of detections
+//	-min-confidence p
+//		ignore detections with a confidence below p; needs -calibration.
+//		The confidence is that of the language in the page's detection.
+//
+// Files that can't be read are reported and skipped. The exit status is
+// 1 if anything was reported and 2 on errors.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"cld2"
+	"cld2/internal/htmlblock"
+)
+
+var (
+	jsonOutput      = flag.Bool("json", false, "write findings as JSON lines")
+	minBytes        = flag.Int("min-bytes", 40, "ignore elements with fewer bytes of text in the detected language")
+	calibrationFile = flag.String("calibration", "", "calibration `file` saved as JSON")
+	minConfidence   = flag.Float64("min-confidence", 0, "ignore detections with lower confidence")
+)
+
+// calibration is loaded from calibrationFile, if set.
+var calibration *cld2.Calibration
+
+// finding is an element whose text is not in its declared language.
+type finding struct {
+	File       string        `json:"file"`
+	Tag        string        `json:"tag"`
+	Offset     int           `json:"offset"`
+	Length     int           `json:"length"`
+	Lang       string        `json:"lang"` // declared or inherited; "" if none
+	Inherited  bool          `json:"inherited,omitempty"`
+	Page       bool          `json:"page,omitempty"` // Lang is what the page declares, as codes
+	Detected   cld2.Language `json:"detected"`
+	Confidence float64       `json:"confidence,omitempty"`
+	TextBytes  int           `json:"text_bytes"`
+}
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: cld2-lang-audit [flags] path...\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if *minConfidence > 0 && *calibrationFile == "" {
+		fatalf("-min-confidence needs -calibration")
+	}
+	if *calibrationFile != "" {
+		f, err := os.Open(*calibrationFile)
+		if err != nil {
+			fatalf("%v", err)
+		}
+		calibration, err = cld2.LoadCalibration(f)
+		f.Close()
+		if err != nil {
+			fatalf("%v", err)
+		}
+	}
+
+	status := 0
+	enc := json.NewEncoder(os.Stdout)
+	for _, root := range flag.Args() {
+		err := auditTree(root, func(f finding) {
+			if status == 0 {
+				status = 1
+			}
+			if !*jsonOutput {
+				report(f)
+			} else if err := enc.Encode(f); err != nil {
+				fatalf("%v", err)
+			}
+		})
+		if err != nil {
+			status = 2
+		}
+	}
+	os.Exit(status)
+}
+
+// auditTree audits root, an HTML file or a directory of them, and
+// passes each finding to found. Files and directories that can't be
+// read are reported and skipped; the first such error is returned.
+func auditTree(root string, found func(finding)) error {
+	var first error
+	fail := func(err error) {
+		fmt.Fprintf(os.Stderr, "cld2-lang-audit: %v\n", err)
+		if first == nil {
+			first = err
+		}
+	}
+	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			fail(err)
+			return nil
+		}
+		if info.IsDir() || (path != root && !isHTML(path)) {
+			return nil
+		}
+		html, err := ioutil.ReadFile(path)
+		if err != nil {
+			fail(err)
+			return nil
+		}
+		for _, f := range audit(path, html) {
+			found(f)
+		}
+		return nil
+	})
+	return first
+}
+
+// isHTML reports whether path names an HTML file.
+func isHTML(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".html", ".htm", ".xhtml":
+		return true
+	}
+	return false
+}
+
+// audit returns the elements of an HTML page whose text is not in the
+// language they declare.
+func audit(path string, html []byte) []finding {
+	// Lang attributes are priors in CLD2's HTML mode and make it whack
+	// the other languages of their close set, so detect the text alone.
+	text, offsets := cld2.ExtractText(html)
+	res, _ := cld2.DetectWithOptions(text, cld2.Options{Spans: true, Offsets: cld2.InputCoordinates, Calibration: calibration})
+	spans := make([]cld2.Span, 0, len(res.Spans))
+	for _, s := range res.Spans {
+		start, end := offsets.MapBack(s.Offset), offsets.MapBack(s.Offset+s.Length)
+		spans = append(spans, cld2.Span{Offset: start, Length: end - start, Language: s.Language})
+	}
+
+	var out []finding
+	page := cld2.DeclaredLanguages(html, 0)
+	for _, b := range htmlblock.Scan(html) {
+		lang, n := blockLanguage(b.Text, spans)
+		if lang == cld2.UNKNOWN_LANGUAGE || n < *minBytes {
+			continue
+		}
+		conf := 0.0
+		for _, e := range res.Estimates {
+			if e.Language == lang {
+				conf = e.Confidence
+			}
+		}
+		if conf < *minConfidence {
+			continue
+		}
+		f := finding{
+			File:       path,
+			Tag:        b.Tag,
+			Offset:     b.Offset,
+			Length:     b.Length,
+			Lang:       b.Lang,
+			Inherited:  !b.Declared,
+			Detected:   lang,
+			Confidence: conf,
+			TextBytes:  n,
+		}
+		declared := page
+		if b.Lang != "" {
+			declared = tagLanguages(b.Lang)
+			if len(declared) == 0 {
+				// A tag CLD2 doesn't know, such as a private use tag
+				continue
+			}
+		} else if len(page) > 0 {
+			f.Lang, f.Page = languageCodes(page), true
+		}
+		if sameLanguage(declared, lang) {
+			continue
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
+// blockLanguage returns the language of most bytes of the text ranges
+// of a block, by spans, and how many bytes are in it.
+func blockLanguage(text []htmlblock.Range, spans []cld2.Span) (cld2.Language, int) {
+	count := make(map[cld2.Language]int)
+	best := cld2.UNKNOWN_LANGUAGE
+	for _, r := range text {
+		for _, s := range spans {
+			start, end := s.Offset, s.Offset+s.Length
+			if start < r.Offset {
+				start = r.Offset
+			}
+			if end > r.Offset+r.Length {
+				end = r.Offset + r.Length
+			}
+			if start >= end || s.Language == cld2.UNKNOWN_LANGUAGE {
+				continue
+			}
+			count[s.Language] += end - start
+			if best == cld2.UNKNOWN_LANGUAGE || count[s.Language] > count[best] {
+				best = s.Language
+			}
+		}
+	}
+	return best, count[best]
+}
+
+// tagLanguages returns the languages CLD2 takes a lang attribute value
+// to declare, as DeclaredLanguages does.
+func tagLanguages(tag string) []cld2.Language {
+	var langs []cld2.Language
+	for _, p := range cld2.PriorsForLangTag(tag).List() {
+		if p.Weight > 0 {
+			langs = append(langs, p.Language)
+		}
+	}
+	return langs
+}
+
+// serboCroatian are the languages CLD2 can't tell apart reliably.
+var serboCroatian = map[cld2.Language]bool{
+	cld2.BOSNIAN: true, cld2.CROATIAN: true, cld2.SERBIAN: true, cld2.MONTENEGRIN: true,
+}
+
+// sameLanguage reports whether lang matches one of langs: the same
+// language, one with the same primary code, or both Serbo-Croatian.
+func sameLanguage(langs []cld2.Language, lang cld2.Language) bool {
+	for _, l := range langs {
+		if l == lang || primaryCode(l) == primaryCode(lang) || serboCroatian[l] && serboCroatian[lang] {
+			return true
+		}
+	}
+	return false
+}
+
+// primaryCode returns the code of l up to the first "-", so "zh-Hant"
+// is "zh".
+func primaryCode(l cld2.Language) string {
+	code := l.Code()
+	if i := strings.IndexByte(code, '-'); i >= 0 {
+		code = code[:i]
+	}
+	return code
+}
+
+// languageCodes returns the codes of langs separated by commas.
+func languageCodes(langs []cld2.Language) string {
+	codes := make([]string, len(langs))
+	for i, l := range langs {
+		codes[i] = l.Code()
+	}
+	return strings.Join(codes, ",")
+}
+
+// report writes a finding as a line of text.
+func report(f finding) {
+	lang := "no lang"
+	switch {
+	case f.Page:
+		lang = fmt.Sprintf("page declares %s", f.Lang)
+	case f.Lang != "":
+		lang = fmt.Sprintf("lang=%q", f.Lang)
+		if f.Inherited {
+			lang += " (inherited)"
+		}
+	}
+	confidence := ""
+	if calibration != nil {
+		confidence = fmt.Sprintf(", confidence %.2f", f.Confidence)
+	}
+	fmt.Printf("%s:%d+%d: <%s> %s but text is %s (%s)%s\n",
+		f.File, f.Offset, f.Length, f.Tag, lang, f.Detected, f.Detected.Code(), confidence)
+}
+
+func fatalf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "cld2-lang-audit: "+format+"\n", args...)
+	os.Exit(2)
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"cld2"
+	"cld2/internal/htmlblock"
+)
+
+const (
+	englishText  = "The quick brown fox jumps over the lazy dog while the children watch from the garden and laugh at the sight."
+	germanText   = "Der schnelle braune Fuchs springt über den faulen Hund, während die Kinder im Garten zuschauen und darüber lachen."
+	spanishText  = "El rápido zorro marrón salta sobre el perro perezoso mientras los niños miran desde el jardín y se ríen de la escena."
+	croatianText = "Brza smeđa lisica preskače lijenog psa dok djeca gledaju iz vrta i smiju se tom prizoru, a susjedi im se pridružuju."
+)
+
+func TestIsHTML(t *testing.T) {
+	for path, want := range map[string]bool{
+		"index.html":      true,
+		"a/b/PAGE.HTM":    true,
+		"doc.xhtml":       true,
+		"style.css":       false,
+		"html":            false,
+		"notes.html.orig": false,
+	} {
+		if got := isHTML(path); got != want {
+			t.Errorf("%s: want %v, got %v", path, want, got)
+		}
+	}
+}
+
+func TestSameLanguage(t *testing.T) {
+	for _, tc := range []struct {
+		declared []cld2.Language
+		lang     cld2.Language
+		want     bool
+	}{
+		{[]cld2.Language{cld2.ENGLISH, cld2.GERMAN}, cld2.GERMAN, true},
+		{[]cld2.Language{cld2.ENGLISH}, cld2.GERMAN, false},
+		{[]cld2.Language{cld2.CROATIAN}, cld2.SERBIAN, true},
+		{[]cld2.Language{cld2.SERBIAN}, cld2.MONTENEGRIN, true},
+		{[]cld2.Language{cld2.CHINESE}, cld2.CHINESE_T, true},
+		{[]cld2.Language{cld2.PORTUGUESE}, cld2.SPANISH, false},
+		{[]cld2.Language{cld2.CZECH}, cld2.SLOVAK, false},
+		{[]cld2.Language{cld2.NORWEGIAN}, cld2.DANISH, false},
+		{nil, cld2.ENGLISH, false},
+	} {
+		if got := sameLanguage(tc.declared, tc.lang); got != tc.want {
+			t.Errorf("%v has %v: want %v, got %v", tc.declared, tc.lang, tc.want, got)
+		}
+	}
+	if got := languageCodes([]cld2.Language{cld2.GERMAN, cld2.FRENCH}); got != "de,fr" {
+		t.Errorf("want de,fr, got %q", got)
+	}
+}
+
+func TestBlockLanguage(t *testing.T) {
+	text := []htmlblock.Range{{Offset: 10, Length: 20}, {Offset: 40, Length: 10}}
+	spans := []cld2.Span{
+		{Offset: 0, Length: 15, Language: cld2.GERMAN},
+		{Offset: 15, Length: 30, Language: cld2.UNKNOWN_LANGUAGE},
+		{Offset: 45, Length: 20, Language: cld2.ENGLISH},
+	}
+	if lang, n := blockLanguage(text, spans); lang != cld2.GERMAN || n != 5 {
+		t.Errorf("want 5 bytes of German, got %d of %v", n, lang)
+	}
+	spans[2].Offset = 40
+	if lang, n := blockLanguage(text, spans); lang != cld2.ENGLISH || n != 10 {
+		t.Errorf("want 10 bytes of English, got %d of %v", n, lang)
+	}
+	if lang, n := blockLanguage(text, nil); lang != cld2.UNKNOWN_LANGUAGE || n != 0 {
+		t.Errorf("want nothing without spans, got %d of %v", n, lang)
+	}
+}
+
+func TestAudit(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		html string
+		want []finding
+	}{
+		{
+			name: "matching",
+			html: `<html lang="en"><body><p>` + englishText + `</p></body></html>`,
+		},
+		{
+			name: "inherited",
+			html: `<html lang="en"><body><p>` + englishText + `</p><p>` + germanText + `</p></body></html>`,
+			want: []finding{{Tag: "p", Lang: "en", Inherited: true, Detected: cld2.GERMAN}},
+		},
+		{
+			name: "declared",
+			html: `<html lang="en"><body><p lang="de">` + englishText + `</p></body></html>`,
+			want: []finding{{Tag: "p", Lang: "de", Detected: cld2.ENGLISH}},
+		},
+		{
+			name: "meta",
+			html: `<html><head><meta http-equiv="content-language" content="de"></head><body><p>` + englishText + `</p></body></html>`,
+			want: []finding{{Tag: "p", Lang: "de", Inherited: true, Page: true, Detected: cld2.ENGLISH}},
+		},
+		{
+			name: "none",
+			html: `<html><body><p>` + germanText + `</p></body></html>`,
+			want: []finding{{Tag: "p", Inherited: true, Detected: cld2.GERMAN}},
+		},
+		{
+			name: "close language",
+			html: `<html lang="pt"><body><p>` + spanishText + `</p></body></html>`,
+			want: []finding{{Tag: "p", Lang: "pt", Inherited: true, Detected: cld2.SPANISH}},
+		},
+		{
+			name: "serbo-croatian",
+			html: `<html lang="sr"><body><p>` + croatianText + `</p></body></html>`,
+		},
+		{
+			name: "unknown tag",
+			html: `<html lang="x-klingon-ish"><body><p>` + germanText + `</p></body></html>`,
+		},
+	} {
+		got := audit(tc.name, []byte(tc.html))
+		if len(got) != len(tc.want) {
+			t.Errorf("%s: want %d findings, got %+v", tc.name, len(tc.want), got)
+			continue
+		}
+		for i, w := range tc.want {
+			g := got[i]
+			if g.Tag != w.Tag || g.Lang != w.Lang || g.Inherited != w.Inherited || g.Page != w.Page || g.Detected != w.Detected {
+				t.Errorf("%s: want %+v, got %+v", tc.name, w, g)
+			}
+			if tc.html[g.Offset:g.Offset+2] != "<"+g.Tag[:1] {
+				t.Errorf("%s: want the offset of <%s>, got %d", tc.name, g.Tag, g.Offset)
+			}
+		}
+	}
+}
+
+func TestAuditTree(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cld2-lang-audit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	page := `<html lang="en"><body><p>` + germanText + `</p></body></html>`
+	for _, name := range []string{"a.html", "c.html", "skip.txt"} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(page), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A file that can't be read, between the others
+	if err := os.Symlink(filepath.Join(dir, "missing"), filepath.Join(dir, "b.html")); err != nil {
+		t.Fatal(err)
+	}
+
+	var files []string
+	err = auditTree(dir, func(f finding) { files = append(files, filepath.Base(f.File)) })
+	if err == nil {
+		t.Error("want an error for the unreadable file")
+	}
+	if len(files) != 2 || files[0] != "a.html" || files[1] != "c.html" {
+		t.Errorf("want findings in a.html and c.html, got %v", files)
+	}
+}
@@ -0,0 +1,330 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"cld2"
+)
+
+const batchUsage = `usage: cld2 batch -fields path[,path...] [flags] [file...]
+
+Batch reads JSON Lines records from the files, or standard input, and
+writes each record to standard output with the languages detected in
+the text of the fields added. Fields are dotted paths such as "title"
+or "body.text"; a path element may index an array, and an array of
+strings is detected as its joined strings.
+
+Each field is detected on its own, and the results are added as an
+object with a member for each field that has text, named by its path:
+
+	"lang": {"title": {...}, "body.text": {...}}
+
+With -join the text of all fields of a record is detected together,
+and the one result is added instead.
+
+Records keep their order, their bytes and their line endings. The
+result is added as the last member, or replaces the value of a member
+of the same name where it is. Lines that aren't JSON objects are
+written unchanged and reported by file and line. Throughput is reported on standard error at the end.
+
+`
+
+// batchConfig is what batch detects in each record and how it adds the
+// results.
+type batchConfig struct {
+	fields []string   // the dotted paths as given
+	paths  [][]string // the paths split at dots
+	key    string     // the member to add
+	join   bool       // detect all fields together
+	opts   cld2.Options
+}
+
+// batchRecord is a line of input and what becomes of it.
+type batchRecord struct {
+	file  string
+	line  int
+	in    []byte
+	out   []byte
+	err   error
+	bytes int // text bytes detected
+	done  chan struct{}
+}
+
+func batchMain(args []string) {
+	fs := flag.NewFlagSet("batch", flag.ExitOnError)
+	fields := fs.String("fields", "", "comma-separated dotted `paths` of the fields to detect")
+	key := fs.String("key", "lang", "`name` of the member to add")
+	join := fs.Bool("join", false, "detect the text of all fields together")
+	workers := fs.Int("workers", runtime.NumCPU(), "`n` records detected in parallel")
+	html := fs.Bool("html", false, "fields are HTML")
+	fs.Usage = func() {
+		fmt.Fprint(os.Stderr, batchUsage)
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if *fields == "" || *workers < 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	cfg := &batchConfig{key: *key, join: *join, opts: cld2.Options{HTML: *html}}
+	for _, f := range strings.Split(*fields, ",") {
+		f = strings.TrimSpace(f)
+		cfg.fields = append(cfg.fields, f)
+		cfg.paths = append(cfg.paths, strings.Split(f, "."))
+	}
+
+	files := fs.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+
+	// The reader hands each record to the workers and, in order, to the
+	// writer, which waits for each to be done.
+	jobs := make(chan *batchRecord, *workers)
+	ordered := make(chan *batchRecord, 4**workers)
+	var readErrs []error // set before ordered is closed
+	go func() {
+		for _, name := range files {
+			if err := readRecords(name, jobs, ordered); err != nil {
+				readErrs = append(readErrs, err)
+			}
+		}
+		close(jobs)
+		close(ordered)
+	}()
+	for i := 0; i < *workers; i++ {
+		go func() {
+			for rec := range jobs {
+				rec.out, rec.bytes, rec.err = annotateRecord(rec.in, cfg)
+				close(rec.done)
+			}
+		}()
+	}
+
+	start := time.Now()
+	w := bufio.NewWriter(os.Stdout)
+	status, records, text := 0, 0, 0
+	for rec := range ordered {
+		<-rec.done
+		records++
+		text += rec.bytes
+		if rec.err != nil {
+			fmt.Fprintf(os.Stderr, "cld2 batch: %s:%d: %v\n", rec.file, rec.line, rec.err)
+			status = 1
+			w.Write(rec.in)
+			continue
+		}
+		w.Write(rec.out)
+	}
+	if err := w.Flush(); err != nil {
+		fatalf("%v", err)
+	}
+	for _, err := range readErrs {
+		fmt.Fprintf(os.Stderr, "cld2 batch: %v\n", err)
+		status = 2
+	}
+	secs := time.Since(start).Seconds()
+	fmt.Fprintf(os.Stderr, "cld2 batch: %d records, %.1f MB of text in %.1fs: %.0f records/s, %.2f MB/s\n",
+		records, float64(text)/1e6, secs, float64(records)/secs, float64(text)/1e6/secs)
+	os.Exit(status)
+}
+
+// readRecords sends each line of the named file, or standard input for
+// "-", as a record to jobs and ordered. A last line without a newline
+// gets one, so that it isn't run together with the next file.
+func readRecords(name string, jobs, ordered chan<- *batchRecord) error {
+	var f io.Reader = os.Stdin
+	if name != "-" {
+		file, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		f = file
+	}
+	r := bufio.NewReader(f)
+	for n := 1; ; n++ {
+		line, err := r.ReadBytes('\n')
+		if len(line) > 0 {
+			if line[len(line)-1] != '\n' {
+				line = append(line, '\n')
+			}
+			rec := &batchRecord{file: name, line: n, in: line, done: make(chan struct{})}
+			ordered <- rec
+			jobs <- rec
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s:%d: %v", name, n, err)
+		}
+	}
+}
+
+// annotateRecord detects the text of the fields of a JSON object and
+// returns it with the results added as cfg.key, and the bytes detected.
+// The line keeps its ending, "\n" or "\r\n".
+func annotateRecord(line []byte, cfg *batchConfig) ([]byte, int, error) {
+	body := bytes.TrimRight(line, "\r\n")
+	eol := line[len(body):]
+	if len(eol) == 0 {
+		eol = []byte{'\n'}
+	}
+	rec := bytes.TrimRight(body, " \t")
+	if len(bytes.TrimSpace(rec)) == 0 {
+		return line, 0, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(rec))
+	dec.UseNumber()
+	var v map[string]interface{}
+	if err := dec.Decode(&v); err != nil || v == nil {
+		return nil, 0, fmt.Errorf("not a JSON object")
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, 0, fmt.Errorf("not a JSON object")
+	}
+
+	var result []byte
+	n := 0
+	if cfg.join {
+		var text []string
+		for _, p := range cfg.paths {
+			text = appendText(text, lookup(v, p))
+		}
+		joined := []byte(strings.Join(text, "\n"))
+		res, err := cld2.DetectWithOptions(joined, cfg.opts)
+		if err != nil {
+			return nil, 0, err
+		}
+		if result, err = json.Marshal(res); err != nil {
+			return nil, 0, err
+		}
+		n = len(joined)
+	} else {
+		// One member per field with text, in the order of the fields
+		result = append(result, '{')
+		for i, p := range cfg.paths {
+			text := appendText(nil, lookup(v, p))
+			if len(text) == 0 {
+				continue
+			}
+			joined := []byte(strings.Join(text, "\n"))
+			res, err := cld2.DetectWithOptions(joined, cfg.opts)
+			if err != nil {
+				return nil, 0, err
+			}
+			field, _ := json.Marshal(cfg.fields[i])
+			value, err := json.Marshal(res)
+			if err != nil {
+				return nil, 0, err
+			}
+			if len(result) > 1 {
+				result = append(result, ',')
+			}
+			result = append(result, field...)
+			result = append(result, ':')
+			result = append(result, value...)
+			n += len(joined)
+		}
+		result = append(result, '}')
+	}
+
+	if _, ok := v[cfg.key]; ok {
+		// Replace the value where it is, keeping the rest as it was
+		ranges, err := memberValues(rec, cfg.key)
+		if err != nil {
+			return nil, 0, err
+		}
+		out := make([]byte, 0, len(line)+len(result))
+		pos := 0
+		for _, r := range ranges {
+			out = append(out, body[pos:r[0]]...)
+			out = append(out, result...)
+			pos = r[1]
+		}
+		out = append(out, body[pos:]...)
+		return append(out, eol...), n, nil
+	}
+	keyJSON, _ := json.Marshal(cfg.key)
+	out := make([]byte, 0, len(line)+len(keyJSON)+len(result)+3)
+	out = append(out, rec[:len(rec)-1]...)
+	if len(v) > 0 {
+		out = append(out, ',')
+	}
+	out = append(out, keyJSON...)
+	out = append(out, ':')
+	out = append(out, result...)
+	out = append(out, '}')
+	out = append(out, body[len(rec):]...)
+	return append(out, eol...), n, nil
+}
+
+// memberValues returns the start and end offsets of the values of the
+// members named key of the JSON object rec, in order.
+func memberValues(rec []byte, key string) ([][2]int, error) {
+	dec := json.NewDecoder(bytes.NewReader(rec))
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return nil, fmt.Errorf("not a JSON object")
+	}
+	var ranges [][2]int
+	for dec.More() {
+		name, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+		if name == key {
+			end := int(dec.InputOffset())
+			ranges = append(ranges, [2]int{end - len(value), end})
+		}
+	}
+	return ranges, nil
+}
+
+// lookup returns the value at a dotted path in v, or nil.
+func lookup(v interface{}, path []string) interface{} {
+	for _, p := range path {
+		switch x := v.(type) {
+		case map[string]interface{}:
+			v = x[p]
+		case []interface{}:
+			i, err := strconv.Atoi(p)
+			if err != nil || i < 0 || i >= len(x) {
+				return nil
+			}
+			v = x[i]
+		default:
+			return nil
+		}
+	}
+	return v
+}
+
+// appendText appends the strings in v, a string or an array of them.
+func appendText(text []string, v interface{}) []string {
+	switch x := v.(type) {
+	case string:
+		text = append(text, x)
+	case []interface{}:
+		for _, e := range x {
+			if s, ok := e.(string); ok {
+				text = append(text, s)
+			}
+		}
+	}
+	return text
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"cld2"
+)
+
+const (
+	enText = "The quick brown fox jumps over the lazy dog while the children watch from the garden and laugh."
+	deText = "Der schnelle braune Fuchs springt über den faulen Hund, während die Kinder im Garten zuschauen."
+)
+
+func TestLookup(t *testing.T) {
+	var v interface{}
+	dec := json.NewDecoder(strings.NewReader(`{"a": {"b": ["x", {"c": "y"}]}, "n": 1}`))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		path string
+		want interface{}
+	}{
+		{"a.b.0", "x"},
+		{"a.b.1.c", "y"},
+		{"a.b.2", nil},
+		{"a.b.-1", nil},
+		{"a.b.x", nil},
+		{"a.missing", nil},
+		{"n.c", nil},
+	} {
+		if got := lookup(v, strings.Split(tc.path, ".")); got != tc.want {
+			t.Errorf("%s: want %v, got %v", tc.path, tc.want, got)
+		}
+	}
+}
+
+func TestAppendText(t *testing.T) {
+	text := appendText(nil, "a")
+	text = appendText(text, []interface{}{"b", json.Number("1"), "c"})
+	text = appendText(text, map[string]interface{}{"d": "e"})
+	text = appendText(text, nil)
+	if strings.Join(text, ",") != "a,b,c" {
+		t.Errorf("want a,b,c, got %q", text)
+	}
+}
+
+func TestMemberValues(t *testing.T) {
+	rec := []byte(`{ "lang" : {"x": [1, 2]}, "id": 12345678901234567890,"lang":"s" }`)
+	got, err := memberValues(rec, "lang")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var values []string
+	for _, r := range got {
+		values = append(values, string(rec[r[0]:r[1]]))
+	}
+	if len(values) != 2 || values[0] != `{"x": [1, 2]}` || values[1] != `"s"` {
+		t.Errorf("want both values of lang, got %q", values)
+	}
+	if _, err := memberValues([]byte(`["lang"]`), "lang"); err == nil {
+		t.Error("want an error for an array")
+	}
+}
+
+func TestAnnotateRecord(t *testing.T) {
+	cfg := &batchConfig{
+		fields: []string{"title", "body.text", "missing"},
+		paths:  [][]string{{"title"}, {"body", "text"}, {"missing"}},
+		key:    "lang",
+	}
+	// Numbers beyond float64, HTML characters and the order of members
+	// must survive.
+	rec := `{"id": 12345678901234567890, "title": "` + enText + ` <&>", "body": {"text": "` + deText + `"}}`
+	out, n, err := annotateRecord([]byte(rec+"\n"), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(out, []byte(rec[:len(rec)-1]+`,"lang":{"title":{`)) || !bytes.HasSuffix(out, []byte("}\n")) {
+		t.Errorf("want the record with lang appended, got %s", out)
+	}
+	if n != len(enText)+4+len(deText) {
+		t.Errorf("want %d bytes detected, got %d", len(enText)+4+len(deText), n)
+	}
+	var got struct {
+		Lang map[string]cld2.Languages `json:"lang"`
+	}
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Lang) != 2 {
+		t.Errorf("want results for the two fields with text, got %+v", got.Lang)
+	}
+	for field, want := range map[string]cld2.Language{"title": cld2.ENGLISH, "body.text": cld2.GERMAN} {
+		if res := got.Lang[field]; len(res.Estimates) == 0 || res.Estimates[0].Language != want {
+			t.Errorf("%s: want %v, got %+v", field, want, res)
+		}
+	}
+
+	// An existing member is replaced where it is.
+	cfg.join = true
+	rec = `{"lang": "old", "id": 12345678901234567890, "title": "` + deText + `"}`
+	out, _, err = annotateRecord([]byte(rec), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(out, []byte(`{"lang": {"estimates":[{"code":"de"`)) ||
+		!bytes.HasSuffix(out, []byte(`, "id": 12345678901234567890, "title": "`+deText+`"}`+"\n")) {
+		t.Errorf("want lang replaced in place, got %s", out)
+	}
+
+	for _, bad := range []string{`[1, 2]`, `{"a": 1} {"b": 2}`, `{"a":`, `null`} {
+		if _, _, err := annotateRecord([]byte(bad), cfg); err == nil {
+			t.Errorf("%s: want an error", bad)
+		}
+	}
+	if out, _, err := annotateRecord([]byte("  \n"), cfg); err != nil || string(out) != "  \n" {
+		t.Errorf("want blank lines unchanged, got %q, %v", out, err)
+	}
+
+	// CRLF line endings and trailing blanks are kept.
+	for _, rec := range []string{`{"title": "` + deText + `"} ` + "\r\n", `{"lang": 1, "title": "` + deText + `"}` + "\r\n"} {
+		out, _, err := annotateRecord([]byte(rec), cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.HasSuffix(out, []byte(rec[strings.LastIndexByte(rec, '}'):])) || bytes.Count(out, []byte("\n")) != 1 {
+			t.Errorf("want the line ending of %q kept, got %q", rec, out)
+		}
+	}
+}
+
+func TestReadRecords(t *testing.T) {
+	dir, err := ioutil.TempDir("", "batch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	a, b := filepath.Join(dir, "a.jsonl"), filepath.Join(dir, "b.jsonl")
+	if err := ioutil.WriteFile(a, []byte("{\"n\": 1}\r\n{\"n\": 2}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(b, []byte("{\"n\": 3}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	jobs, ordered := make(chan *batchRecord, 10), make(chan *batchRecord, 10)
+	for _, name := range []string{a, b} {
+		if err := readRecords(name, jobs, ordered); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := readRecords(filepath.Join(dir, "missing"), jobs, ordered); err == nil {
+		t.Error("want an error for a missing file")
+	}
+	close(ordered)
+	var got []string
+	for rec := range ordered {
+		got = append(got, fmt.Sprintf("%s:%d:%q", filepath.Base(rec.file), rec.line, rec.in))
+	}
+	want := []string{`a.jsonl:1:"{\"n\": 1}\r\n"`, `a.jsonl:2:"{\"n\": 2}\n"`, `b.jsonl:1:"{\"n\": 3}\n"`}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
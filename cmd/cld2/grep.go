@@ -0,0 +1,204 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"cld2"
+)
+
+const grepUsage = `usage: cld2 grep -l lang[,lang...] [flags] [file...]
+
+Grep prints the lines of the files, or standard input, that are in one
+of the languages, given as tags such as "de" or "pt-BR". With -p it
+looks at paragraphs, separated by blank lines, instead. The language of
+a line or paragraph is the one most of its spans are in.
+
+A language matches a tag if it is the same language or has the same
+primary code, so "zh" matches traditional Chinese and "pt-BR" matches
+Portuguese. With -close it also matches the languages CLD2 can't tell
+apart from it: "no" then matches Nynorsk and Danish, "hr" Bosnian and
+Serbian, and "pt" Spanish and Galician.
+
+With -reliable, CLD2 must also see the detection as reliable. With
+-min-confidence, the language must also be detected with at least that
+confidence, which comes from a calibration fitted with
+cld2.FitCalibration and named with -calibration.
+
+The exit status is 0 if something was printed, 1 if not, and 2 on
+errors.
+
+`
+
+// paragraphBreak separates paragraphs: a blank line.
+var paragraphBreak = regexp.MustCompile(`\n[ \t\r]*\n`)
+
+func grepMain(args []string) {
+	fs := flag.NewFlagSet("grep", flag.ExitOnError)
+	list := fs.String("l", "", "comma-separated language `tags` to keep")
+	paragraphs := fs.Bool("p", false, "match paragraphs instead of lines")
+	invert := fs.Bool("v", false, "print what is not in the languages")
+	closeLangs := fs.Bool("close", false, "also match languages CLD2 can't tell apart from the given ones")
+	reliable := fs.Bool("reliable", false, "only match what CLD2 detects reliably")
+	calibrationFile := fs.String("calibration", "", "calibration `file` saved as JSON")
+	minConfidence := fs.Float64("min-confidence", 0, "minimum confidence `p` of the language; needs -calibration")
+	html := fs.Bool("html", false, "input is HTML")
+	fs.Usage = func() {
+		fmt.Fprint(os.Stderr, grepUsage)
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if *list == "" {
+		fs.Usage()
+		os.Exit(2)
+	}
+	langs := newLanguageSet(*closeLangs)
+	for _, tag := range strings.Split(*list, ",") {
+		l := cld2.LanguageFromTag(tag)
+		if l == cld2.UNKNOWN_LANGUAGE {
+			fatalf("unknown language %q", tag)
+		}
+		langs.add(l)
+	}
+	opts := cld2.Options{HTML: *html}
+	if *minConfidence > 0 && *calibrationFile == "" {
+		fatalf("-min-confidence needs -calibration")
+	}
+	if *calibrationFile != "" {
+		c, err := loadCalibration(*calibrationFile)
+		if err != nil {
+			fatalf("%v", err)
+		}
+		opts.Calibration = c
+	}
+
+	files := fs.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	w := bufio.NewWriter(os.Stdout)
+	status := 1
+	for _, name := range files {
+		data, err := readInput(name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cld2: %v\n", err)
+			status = 2
+			continue
+		}
+		for _, unit := range splitUnits(data, *paragraphs) {
+			lang, conf, ok := spanLanguage(unit, opts)
+			if (langs.has(lang) && conf >= *minConfidence && (ok || !*reliable)) == *invert {
+				continue
+			}
+			if status == 1 {
+				status = 0
+			}
+			if len(files) > 1 {
+				fmt.Fprintf(w, "%s:", name)
+			}
+			w.Write(unit)
+			if !bytes.HasSuffix(unit, []byte("\n")) {
+				w.WriteByte('\n')
+			}
+			if *paragraphs {
+				w.WriteByte('\n')
+			}
+		}
+	}
+	if err := w.Flush(); err != nil {
+		fatalf("%v", err)
+	}
+	os.Exit(status)
+}
+
+// languageSet is the languages given to grep by primary language code
+// and, if it matches close languages, by close set.
+type languageSet struct {
+	codes  map[string]bool
+	groups map[string]bool // nil unless close languages match
+}
+
+func newLanguageSet(close bool) languageSet {
+	s := languageSet{codes: make(map[string]bool)}
+	if close {
+		s.groups = make(map[string]bool)
+	}
+	return s
+}
+
+func (s languageSet) add(l cld2.Language) {
+	s.codes[primaryCode(l)] = true
+	if s.groups != nil {
+		s.groups[l.Group()] = true
+	}
+}
+
+func (s languageSet) has(l cld2.Language) bool {
+	return l != cld2.UNKNOWN_LANGUAGE && (s.codes[primaryCode(l)] || s.groups[l.Group()])
+}
+
+// primaryCode returns the code of l up to the first "-", so "zh-Hant"
+// is "zh".
+func primaryCode(l cld2.Language) string {
+	code := l.Code()
+	if i := strings.IndexByte(code, '-'); i >= 0 {
+		code = code[:i]
+	}
+	return code
+}
+
+// splitUnits splits data into lines, or into paragraphs without the
+// blank lines between them. Blank units are left out.
+func splitUnits(data []byte, paragraphs bool) [][]byte {
+	var units [][]byte
+	if paragraphs {
+		pos := 0
+		for _, m := range paragraphBreak.FindAllIndex(data, -1) {
+			units = append(units, data[pos:m[0]+1])
+			pos = m[1]
+		}
+		units = append(units, data[pos:])
+	} else {
+		units = bytes.SplitAfter(data, []byte("\n"))
+	}
+	out := units[:0]
+	for _, u := range units {
+		if len(bytes.TrimSpace(u)) > 0 {
+			out = append(out, u)
+		}
+	}
+	return out
+}
+
+// spanLanguage detects text and returns the language of most of its
+// span bytes, or UNKNOWN_LANGUAGE if no span has a language, with the
+// confidence of that language and whether the result is reliable.
+func spanLanguage(text []byte, opts cld2.Options) (cld2.Language, float64, bool) {
+	opts.Spans = true
+	res, err := cld2.DetectWithOptions(text, opts)
+	if err != nil {
+		return cld2.UNKNOWN_LANGUAGE, 0, false
+	}
+	count := make(map[cld2.Language]int)
+	best := cld2.UNKNOWN_LANGUAGE
+	for _, s := range res.Spans {
+		if s.Language == cld2.UNKNOWN_LANGUAGE {
+			continue
+		}
+		count[s.Language] += s.Length
+		if best == cld2.UNKNOWN_LANGUAGE || count[s.Language] > count[best] {
+			best = s.Language
+		}
+	}
+	for _, e := range res.Estimates {
+		if e.Language == best {
+			return best, e.Confidence, res.Reliable
+		}
+	}
+	return best, 0, res.Reliable
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"cld2"
+)
+
+func TestSplitUnits(t *testing.T) {
+	data := []byte("one\n\ntwo\nthree\n  \t\nfour")
+	for _, tc := range []struct {
+		paragraphs bool
+		want       []string
+	}{
+		{false, []string{"one\n", "two\n", "three\n", "four"}},
+		{true, []string{"one\n", "two\nthree\n", "four"}},
+	} {
+		var got []string
+		for _, u := range splitUnits(data, tc.paragraphs) {
+			got = append(got, string(u))
+		}
+		if strings.Join(got, "|") != strings.Join(tc.want, "|") {
+			t.Errorf("paragraphs %v: want %q, got %q", tc.paragraphs, tc.want, got)
+		}
+	}
+	if units := splitUnits([]byte(" \n\n"), true); len(units) != 0 {
+		t.Errorf("want no units in blank input, got %q", units)
+	}
+}
+
+func TestLanguageSet(t *testing.T) {
+	for _, tc := range []struct {
+		tags  string
+		close bool
+		lang  cld2.Language
+		want  bool
+	}{
+		{"de", false, cld2.GERMAN, true},
+		{"de", false, cld2.ENGLISH, false},
+		{"en,de", false, cld2.ENGLISH, true},
+		{"zh", false, cld2.CHINESE_T, true},
+		{"zh-Hant", false, cld2.CHINESE, true},
+		{"pt-BR", false, cld2.PORTUGUESE, true},
+		{"es", false, cld2.PORTUGUESE, false},
+		{"pt", false, cld2.SPANISH, false},
+		{"pt", false, cld2.GALICIAN, false},
+		{"cs", false, cld2.SLOVAK, false},
+		{"no", false, cld2.DANISH, false},
+		{"hr", false, cld2.SERBIAN, false},
+		{"es", true, cld2.PORTUGUESE, true},
+		{"pt", true, cld2.SPANISH, true},
+		{"no", true, cld2.NORWEGIAN_N, true},
+		{"no", true, cld2.DANISH, true},
+		{"nn", true, cld2.NORWEGIAN, true},
+		{"hr", true, cld2.SERBIAN, true},
+		{"es", true, cld2.ENGLISH, false},
+		{"de", false, cld2.UNKNOWN_LANGUAGE, false},
+		{"de", true, cld2.UNKNOWN_LANGUAGE, false},
+	} {
+		s := newLanguageSet(tc.close)
+		for _, tag := range strings.Split(tc.tags, ",") {
+			s.add(cld2.LanguageFromTag(tag))
+		}
+		if got := s.has(tc.lang); got != tc.want {
+			t.Errorf("%s (close %v) has %v: want %v, got %v", tc.tags, tc.close, tc.lang, tc.want, got)
+		}
+	}
+}
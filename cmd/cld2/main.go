@@ -0,0 +1,249 @@
+// Command cld2 prints the languages CLD2 detects in text.
+//
+// Usage:
+//
+//	cld2 [flags] [file...]
+//	cld2 batch -fields path[,path...] [flags] [file...]
+//	cld2 grep -l lang[,lang...] [flags] [file...]
+//	cld2 split [flags] [file]
+//
+// Each file is detected on its own; with no files, or for "-", standard
+// input is read. Gzipped input is decompressed. For each input cld2
+// prints the estimated languages with their share of the text, scores
+// and confidence, and whether the result is reliable.
+//
+// The flags are:
+//
+//	-html
+//		the input is HTML: skip tags and use lang attributes
+//	-hint-tld tld
+//		the top-level domain the text came from, such as "de"
+//	-hint-lang tag
+//		the language the text is expected to be in, such as "pt-BR"
+//	-spans
+//		also print the language of each span of the text
+//	-top n
+//		print at most n languages (default 3)
+//	-calibration file
+//		a calibration fitted with cld2.FitCalibration and saved as
+//		JSON, to print the confidence of each language
+//	-json
+//		print the result as JSON: the result for one input, or a line
+//		with "file" and "result" for each of several inputs
+//
+// The exit status is 0 if every result is reliable, 1 if some is not,
+// and 2 on errors.
+//
+// "cld2 batch" detects fields of JSON Lines records in parallel and
+// adds the results to the records. "cld2 grep" prints the lines or
+// paragraphs in some languages, and "cld2 split" writes the spans of a
+// document to a file for each language. Run them with -h for details.
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"cld2"
+)
+
+var (
+	htmlInput = flag.Bool("html", false, "input is HTML")
+	hintTLD   = flag.String("hint-tld", "", "top-level domain hint, such as de")
+	hintLang  = flag.String("hint-lang", "", "language hint, such as pt-BR")
+	spans     = flag.Bool("spans", false, "print the language of each span")
+	top       = flag.Int("top", 3, "print at most `n` languages")
+	jsonOut   = flag.Bool("json", false, "print results as JSON")
+	calFile   = flag.String("calibration", "", "calibration `file` saved as JSON, for confidences")
+)
+
+// commands are the subcommands, by name.
+var commands = map[string]func(args []string){
+	"batch": batchMain,
+	"grep":  grepMain,
+	"split": splitMain,
+}
+
+func main() {
+	if len(os.Args) > 1 {
+		if cmd, ok := commands[os.Args[1]]; ok {
+			cmd(os.Args[2:])
+			return
+		}
+	}
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: cld2 [flags] [file...]\n       cld2 batch -fields path[,path...] [flags] [file...]\n"+
+			"       cld2 grep -l lang[,lang...] [flags] [file...]\n       cld2 split [flags] [file]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *top < 0 {
+		*top = 0
+	}
+	opts, err := detectOptions()
+	if err != nil {
+		fatalf("%v", err)
+	}
+
+	files := flag.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	status := 0
+	for _, name := range files {
+		data, err := readInput(name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cld2: %v\n", err)
+			status = 2
+			continue
+		}
+		res, err := cld2.DetectWithOptions(data, opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cld2: %s: %v\n", name, err)
+			status = 2
+			continue
+		}
+		if len(res.Estimates) > *top {
+			res.Estimates = res.Estimates[:*top]
+		}
+		if *jsonOut {
+			err = printJSON(name, res, len(files) > 1)
+		} else {
+			printText(name, res, len(files) > 1, opts.Calibration != nil)
+		}
+		if err != nil {
+			fatalf("%v", err)
+		}
+		if !res.Reliable && status == 0 {
+			status = 1
+		}
+	}
+	os.Exit(status)
+}
+
+// detectOptions returns the detection options set by the flags.
+func detectOptions() (cld2.Options, error) {
+	opts := cld2.Options{HTML: *htmlInput, Spans: *spans, Offsets: cld2.InputCoordinates}
+	if *calFile != "" {
+		c, err := loadCalibration(*calFile)
+		if err != nil {
+			return opts, err
+		}
+		opts.Calibration = c
+	}
+	if *hintTLD == "" && *hintLang == "" {
+		return opts, nil
+	}
+	hints := cld2.NoHints
+	hints.TLD = strings.ToLower(strings.TrimPrefix(*hintTLD, "."))
+	if *hintLang != "" {
+		hints.Language = cld2.LanguageFromTag(*hintLang)
+		if hints.Language == cld2.UNKNOWN_LANGUAGE {
+			return opts, fmt.Errorf("unknown language %q", *hintLang)
+		}
+	}
+	opts.Hints = &hints
+	return opts, nil
+}
+
+// loadCalibration reads a calibration saved as JSON from the named file.
+func loadCalibration(name string) (*cld2.Calibration, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	c, err := cld2.LoadCalibration(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	return c, nil
+}
+
+// readInput reads the named file, or standard input for "-", and
+// decompresses it if it is gzipped.
+func readInput(name string) ([]byte, error) {
+	var r io.Reader = os.Stdin
+	if name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(data, []byte{0x1f, 0x8b}) {
+		return data, nil
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	data, err = ioutil.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	return data, nil
+}
+
+// printText prints a result for people, with the file name first if
+// there are several inputs, and confidences if they were calibrated.
+func printText(name string, res cld2.Languages, named, calibrated bool) {
+	if named {
+		fmt.Printf("%s:\n", name)
+	}
+	for _, e := range res.Estimates {
+		fmt.Printf("%-24s %3d%%  score %7.1f",
+			fmt.Sprintf("%s (%s)", e.Language, e.Language.Code()), e.Percent, e.NormScore)
+		if calibrated {
+			fmt.Printf("  confidence %.2f", e.Confidence)
+		}
+		fmt.Println()
+	}
+	if len(res.Estimates) == 0 {
+		fmt.Println("no language found")
+	}
+	reliable := "reliable"
+	if !res.Reliable {
+		reliable = "unreliable"
+		if res.Unreliable != "" {
+			reliable += " (" + string(res.Unreliable) + ")"
+		}
+	}
+	fmt.Printf("%s, %d text bytes\n", reliable, res.TextBytes)
+	if res.UTF8.Damaged() {
+		fmt.Printf("repaired %d bytes of ill-formed UTF-8\n", res.UTF8.Bytes)
+	}
+	for _, s := range res.Spans {
+		fmt.Printf("  %8d +%-6d %s (%s)\n", s.Offset, s.Length, s.Language, s.Language.Code())
+	}
+}
+
+// printJSON prints a result as JSON, wrapped with the file name if
+// there are several inputs.
+func printJSON(name string, res cld2.Languages, named bool) error {
+	var v interface{} = res
+	if named {
+		v = struct {
+			File   string         `json:"file"`
+			Result cld2.Languages `json:"result"`
+		}{name, res}
+	}
+	return json.NewEncoder(os.Stdout).Encode(v)
+}
+
+func fatalf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "cld2: "+format+"\n", args...)
+	os.Exit(2)
+}
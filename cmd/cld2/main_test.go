@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"cld2"
+)
+
+func TestReadInput(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cld2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	const text = "Der schnelle braune Fuchs springt über den faulen Hund."
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	zw.Write([]byte(text))
+	zw.Close()
+	files := map[string][]byte{
+		"plain.txt":  []byte(text),
+		"text.gz":    gz.Bytes(),
+		"broken.gz":  gz.Bytes()[:12],
+		"binary.dat": {0x1f},
+	}
+	for name, data := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for name, want := range map[string]string{
+		"plain.txt":  text,
+		"text.gz":    text,
+		"binary.dat": "\x1f",
+	} {
+		got, err := readInput(filepath.Join(dir, name))
+		if err != nil || string(got) != want {
+			t.Errorf("%s: want %q, got %q, %v", name, want, got, err)
+		}
+	}
+	if _, err := readInput(filepath.Join(dir, "broken.gz")); err == nil {
+		t.Error("want an error for truncated gzip")
+	}
+	if _, err := readInput(filepath.Join(dir, "missing")); err == nil {
+		t.Error("want an error for a missing file")
+	}
+}
+
+func TestDetectOptions(t *testing.T) {
+	defer func(tld, lang string) { *hintTLD, *hintLang = tld, lang }(*hintTLD, *hintLang)
+
+	*hintTLD, *hintLang = "", ""
+	opts, err := detectOptions()
+	if err != nil || opts.Hints != nil {
+		t.Errorf("want no hints without hint flags, got %+v, %v", opts.Hints, err)
+	}
+
+	*hintTLD, *hintLang = ".DE", "pt-BR"
+	opts, err = detectOptions()
+	if err != nil || opts.Hints == nil || opts.Hints.TLD != "de" || opts.Hints.Language != cld2.PORTUGUESE {
+		t.Errorf("want TLD de and Portuguese, got %+v, %v", opts.Hints, err)
+	}
+	if opts.Hints.Encoding != cld2.UNKNOWN_ENCODING {
+		t.Errorf("want no encoding hint, got %v", opts.Hints.Encoding)
+	}
+
+	*hintTLD, *hintLang = "", "zzz"
+	if _, err := detectOptions(); err == nil {
+		t.Error("want an error for an unknown language")
+	}
+}
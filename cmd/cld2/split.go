@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"cld2"
+)
+
+const splitUsage = `usage: cld2 split [flags] [file]
+
+Split detects the language of each span of a document, the file or
+standard input, and writes the spans to a file for each language named
+prefix.code.txt, such as "doc.de.txt". Spans are written in order, and
+text CLD2 has no language for goes to prefix.un.txt, so the files have
+every byte of the text between them. HTML is split as its text, with
+tags and entities removed. The files written are listed with their size.
+
+`
+
+// splitFile is an output file.
+type splitFile struct {
+	name string
+	f    *os.File
+	w    *bufio.Writer
+}
+
+// splitSpans writes the spans of text to a writer for each language
+// code, made by create when the code first comes up, with the bytes
+// no span covers written as "un". It returns the bytes written for each
+// code.
+func splitSpans(text []byte, spans []cld2.Span, create func(code string) (io.Writer, error)) (map[string]int, error) {
+	type output struct {
+		w   io.Writer
+		end int // where the last span written ended
+	}
+	outs := make(map[string]*output)
+	sizes := make(map[string]int)
+	write := func(start, end int, lang cld2.Language) error {
+		if end > len(text) {
+			end = len(text)
+		}
+		if start >= end {
+			return nil
+		}
+		code := "un"
+		if lang != cld2.UNKNOWN_LANGUAGE {
+			code = lang.Code()
+		}
+		out := outs[code]
+		if out == nil {
+			w, err := create(code)
+			if err != nil {
+				return err
+			}
+			out = &output{w: w}
+			outs[code] = out
+		} else if out.end != start && text[out.end-1] != '\n' {
+			// Keep spans from apart in the text on lines of their own
+			if _, err := out.w.Write([]byte("\n")); err != nil {
+				return err
+			}
+		}
+		if _, err := out.w.Write(text[start:end]); err != nil {
+			return err
+		}
+		out.end = end
+		sizes[code] += end - start
+		return nil
+	}
+	pos := 0
+	for _, s := range spans {
+		if s.Offset > pos {
+			if err := write(pos, s.Offset, cld2.UNKNOWN_LANGUAGE); err != nil {
+				return nil, err
+			}
+		}
+		start, end := s.Offset, s.Offset+s.Length
+		if start < pos {
+			start = pos
+		}
+		if end <= pos {
+			continue
+		}
+		if err := write(start, end, s.Language); err != nil {
+			return nil, err
+		}
+		pos = end
+	}
+	if err := write(pos, len(text), cld2.UNKNOWN_LANGUAGE); err != nil {
+		return nil, err
+	}
+	return sizes, nil
+}
+
+func splitMain(args []string) {
+	fs := flag.NewFlagSet("split", flag.ExitOnError)
+	prefix := fs.String("o", "", "`prefix` of the output files (default the input name without extension, or \"split\")")
+	html := fs.Bool("html", false, "input is HTML")
+	fs.Usage = func() {
+		fmt.Fprint(os.Stderr, splitUsage)
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() > 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	name := "-"
+	if fs.NArg() == 1 {
+		name = fs.Arg(0)
+	}
+	if *prefix == "" {
+		*prefix = "split"
+		if name != "-" {
+			*prefix = strings.TrimSuffix(name, filepath.Ext(name))
+		}
+	}
+
+	data, err := readInput(name)
+	if err != nil {
+		fatalf("%v", err)
+	}
+	text := data
+	opts := cld2.Options{HTML: *html, Spans: true, Offsets: cld2.InputCoordinates}
+	if *html {
+		text, _ = cld2.ExtractText(data)
+		opts.Offsets = cld2.TextCoordinates
+	}
+	res, err := cld2.DetectWithOptions(data, opts)
+	if err != nil {
+		fatalf("%s: %v", name, err)
+	}
+
+	files := make(map[string]*splitFile)
+	sizes, err := splitSpans(text, res.Spans, func(code string) (io.Writer, error) {
+		sf := &splitFile{name: *prefix + "." + code + ".txt"}
+		var err error
+		if sf.f, err = os.Create(sf.name); err != nil {
+			return nil, err
+		}
+		sf.w = bufio.NewWriter(sf.f)
+		files[code] = sf
+		return sf.w, nil
+	})
+	if err != nil {
+		fatalf("%v", err)
+	}
+
+	codes := make([]string, 0, len(files))
+	for code := range files {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		sf := files[code]
+		if err := sf.w.Flush(); err != nil {
+			fatalf("%v", err)
+		}
+		if err := sf.f.Close(); err != nil {
+			fatalf("%v", err)
+		}
+		fmt.Printf("%-8s %8d bytes  %s\n", code, sizes[code], sf.name)
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"cld2"
+)
+
+func TestSplitSpans(t *testing.T) {
+	text := []byte("Hallo Welt. Hello world. Wie geht's? ...")
+	spans := []cld2.Span{
+		{Offset: 0, Length: 12, Language: cld2.GERMAN},
+		{Offset: 12, Length: 13, Language: cld2.ENGLISH},
+		{Offset: 20, Length: 5, Language: cld2.ENGLISH}, // overlaps the last
+		{Offset: 25, Length: 11, Language: cld2.GERMAN},
+		{Offset: 40, Length: 10, Language: cld2.FRENCH}, // past the end
+	}
+	outs := make(map[string]*bytes.Buffer)
+	sizes, err := splitSpans(text, spans, func(code string) (io.Writer, error) {
+		if outs[code] != nil {
+			t.Errorf("%s created twice", code)
+		}
+		outs[code] = new(bytes.Buffer)
+		return outs[code], nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"de": "Hallo Welt. \nWie geht's?",
+		"en": "Hello world. ",
+		"un": " ...",
+	}
+	if len(outs) != len(want) {
+		t.Errorf("want outputs for de, en and un, got %v", sizes)
+	}
+	total := 0
+	for code, w := range want {
+		if outs[code] == nil || outs[code].String() != w {
+			t.Errorf("%s: want %q, got %q", code, w, outs[code])
+		}
+		total += sizes[code]
+	}
+	if total != len(text) {
+		t.Errorf("want %d bytes written, got %v", len(text), sizes)
+	}
+
+	fail := errors.New("no space")
+	_, err = splitSpans(text, spans, func(string) (io.Writer, error) { return nil, fail })
+	if err != fail {
+		t.Errorf("want the error of create, got %v", err)
+	}
+}
@@ -123,12 +123,21 @@ namespace CLD2 {
   // document, and the language hint from any other context you might have.
   // The lang= tags inside an HTML document will be picked up as hints
   // by code within the compact language detector.
+  //
+  // lang_priors are any number of further priors, packed as OneCLDLangPrior
+  // in compact_lang_det_hint_code.h, such as from a user's language history.
+  // Positive weights boost a language; negative weights rule it out, even
+  // if other hints boost it. Like all priors they are trimmed to the four
+  // with the largest abs weights.
+  // Leave them NULL and 0 if not known.
 
   typedef struct {
     const char* content_language_hint;      // "mi,en" boosts Maori and English
     const char* tld_hint;                   // "id" boosts Indonesian
     int encoding_hint;                      // SJS boosts Japanese
     Language language_hint;                 // ITALIAN boosts it
+    const short* lang_priors;               // packed <Language, weight>
+    int num_lang_priors;
   } CLDHints;
 
   static const int kMaxResultChunkBytes = 65535;
@@ -270,6 +279,7 @@ namespace CLD2 {
   static const int kCLDFlagVerbose =      0x0800;  // More debug HTML => stderr
   static const int kCLDFlagQuiet =        0x1000;  // Less debug HTML => stderr
   static const int kCLDFlagEcho =         0x2000;  // Echo input => stderr
+  static const int kCLDFlagNoLangTags =   0x8000;  // Ignore lang= in HTML
 
 
 /***
@@ -292,6 +302,9 @@ Flag meanings:
    In that HTML file, suppress most of the output detail.
  kCLDFlagEcho
   Echo every input buffer to stderr.
+ kCLDFlagNoLangTags
+  Don't use lang= attributes and Content-Language meta tags in HTML as
+  priors, so that the text is scored as if only the hints given applied.
 ***/
 
 // Debug output: Print the resultchunkvector to file f
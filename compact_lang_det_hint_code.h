@@ -55,6 +55,12 @@ inline void InitCLDLangPriors(CLDLangPriors* lps) {
   lps->n = 0;
 }
 
+// Merge in another language prior, taking max if already there
+void MergeCLDLangPriorsMax(OneCLDLangPrior olp, CLDLangPriors* lps);
+
+// Merge in another language prior, boosting 10x if already there
+void MergeCLDLangPriorsBoost(OneCLDLangPrior olp, CLDLangPriors* lps);
+
 // Trim language priors to no more than max_entries, keeping largest abs weights
 void TrimCLDLangPriors(int max_entries, CLDLangPriors* lps);
 
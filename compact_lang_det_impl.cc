@@ -1138,9 +1138,10 @@ void MoveLang1ToLang2(Language lang1, Language lang2,
 
 
 // Move less likely byte count to more likely for close pairs of languages
-// If given, also update resultchunkvector
+// If given, also update resultchunkvector and record the moves in closepairs
 void RefineScoredClosePairs(DocTote* doc_tote,
                             ResultChunkVector* resultchunkvector,
+                            std::vector<ClosePairMerge>* closepairs,
                             bool FLAGS_cld2_html, bool FLAGS_cld2_quiet) {
   for (int sub = 0; sub < doc_tote->MaxSize(); ++sub) {
     int close_packedlang = doc_tote->Key(sub);
@@ -1182,6 +1183,14 @@ void RefineScoredClosePairs(DocTote* doc_tote,
                   doc_tote->Value(from_sub),
                   LanguageCode(to_lang));
         }
+        if (closepairs != NULL) {
+          ClosePairMerge merge = {from_lang, to_lang,
+                                  doc_tote->Value(from_sub),
+                                  doc_tote->Value(to_sub),
+                                  doc_tote->Score(from_sub),
+                                  doc_tote->Score(to_sub)};
+          closepairs->push_back(merge);
+        }
         MoveLang1ToLang2(from_lang, to_lang, from_sub, to_sub,
                          doc_tote, resultchunkvector);
         break;    // Exit inner for sub2 loop
@@ -1571,14 +1580,50 @@ void AddCloseLangWhack(Language lang, ScoringContext* scoringcontext) {
 }
 
 
+// Largest weight of a language prior boost, the last entry of
+// kLgProbV2TblBackmap
+static const int kMaxLangPriorQprob = 12;
+
+// Merge the priors of one hint into lang_priors, as boosts or by maximum,
+// and note source for each of their languages if sources is given
+static void MergeHintPriors(const CLDLangPriors& hint_priors,
+                            bool boost,
+                            int source,
+                            CLDLangPriors* lang_priors,
+                            std::vector<int>* sources) {
+  for (int i = 0; i < hint_priors.n; ++i) {
+    OneCLDLangPrior olp = hint_priors.prior[i];
+    if (boost) {
+      MergeCLDLangPriorsBoost(olp, lang_priors);
+    } else {
+      MergeCLDLangPriorsMax(olp, lang_priors);
+    }
+    if (sources != NULL) {
+      (*sources)[GetCLDPriorLang(olp)] |= source;
+    }
+  }
+}
+
 void ApplyHints(const char* buffer,
                 int buffer_length,
                 bool is_plain_text,
                 const CLDHints* cld_hints,
-                ScoringContext* scoringcontext) {
+                ScoringContext* scoringcontext,
+                std::vector<AppliedLangPrior>* applied) {
   CLDLangPriors lang_priors;
   InitCLDLangPriors(&lang_priors);
 
+  // Each hint's priors are collected apart first, so that the source of
+  // every prior can be reported
+  CLDLangPriors hint_priors;
+  std::vector<int> source_vec;
+  std::vector<int>* sources = NULL;
+  if (applied != NULL) {
+    applied->clear();
+    source_vec.resize(NUM_LANGUAGES, 0);
+    sources = &source_vec;
+  }
+
   // We now use lang= tags.
   // Last look, circa 2008 found only 15% of web pages with lang= tags and
   // many of those were wrong. Now (July 2011), we find 44% of web pages have
@@ -1589,7 +1634,10 @@ void ApplyHints(const char* buffer,
     int32 max_scan_bytes = FLAGS_cld_max_lang_tag_scan_kb << 10;
     string lang_tags = GetLangTagsFromHtml(buffer, buffer_length,
                                            max_scan_bytes);
-    SetCLDLangTagsHint(lang_tags, &lang_priors);
+    InitCLDLangPriors(&hint_priors);
+    SetCLDLangTagsHint(lang_tags, &hint_priors);
+    MergeHintPriors(hint_priors, false, kPriorSourceLangTags,
+                    &lang_priors, sources);
     if (scoringcontext->flags_cld2_html) {
       if (!lang_tags.empty()) {
         fprintf(scoringcontext->debug_file, "<br>lang_tags '%s'<br>\n",
@@ -1601,27 +1649,73 @@ void ApplyHints(const char* buffer,
   if (cld_hints != NULL) {
     if ((cld_hints->content_language_hint != NULL) &&
         (cld_hints->content_language_hint[0] != '\0')) {
-      SetCLDContentLangHint(cld_hints->content_language_hint, &lang_priors);
+      InitCLDLangPriors(&hint_priors);
+      SetCLDContentLangHint(cld_hints->content_language_hint, &hint_priors);
+      MergeHintPriors(hint_priors, false, kPriorSourceContentLang,
+                      &lang_priors, sources);
     }
 
     // Input is from GetTLD(), already lowercased
     if ((cld_hints->tld_hint != NULL) && (cld_hints->tld_hint[0] != '\0')) {
-      SetCLDTLDHint(cld_hints->tld_hint, &lang_priors);
+      InitCLDLangPriors(&hint_priors);
+      SetCLDTLDHint(cld_hints->tld_hint, &hint_priors);
+      MergeHintPriors(hint_priors, true, kPriorSourceTLD,
+                      &lang_priors, sources);
     }
 
     if (cld_hints->encoding_hint != UNKNOWN_ENCODING) {
       Encoding enc = static_cast<Encoding>(cld_hints->encoding_hint);
-      SetCLDEncodingHint(enc, &lang_priors);
+      InitCLDLangPriors(&hint_priors);
+      SetCLDEncodingHint(enc, &hint_priors);
+      MergeHintPriors(hint_priors, true, kPriorSourceEncoding,
+                      &lang_priors, sources);
     }
 
     if (cld_hints->language_hint != UNKNOWN_LANGUAGE) {
-      SetCLDLanguageHint(cld_hints->language_hint, &lang_priors);
+      InitCLDLangPriors(&hint_priors);
+      SetCLDLanguageHint(cld_hints->language_hint, &hint_priors);
+      MergeHintPriors(hint_priors, true, kPriorSourceLanguage,
+                      &lang_priors, sources);
+    }
+
+    // Any other priors, largest abs weights first so they survive a full
+    // vector. A negative weight replaces any boost the other hints gave
+    // the language, so that it is ruled out below.
+    for (int i = 0; i < cld_hints->num_lang_priors; ++i) {
+      OneCLDLangPrior olp = cld_hints->lang_priors[i];
+      if (olp == 0) {continue;}
+      if (GetCLDPriorWeight(olp) < 0) {
+        int k = 0;
+        while ((k < lang_priors.n) &&
+               (GetCLDPriorLang(lang_priors.prior[k]) != GetCLDPriorLang(olp))) {
+          ++k;
+        }
+        if (k < lang_priors.n) {
+          lang_priors.prior[k] = olp;
+        } else if (lang_priors.n < kMaxOneCLDLangPrior) {
+          lang_priors.prior[lang_priors.n++] = olp;
+        }
+      } else {
+        MergeCLDLangPriorsMax(olp, &lang_priors);
+      }
+      if (sources != NULL) {
+        (*sources)[GetCLDPriorLang(olp)] |= kPriorSourceCustom;
+      }
     }
   }
 
   // Keep no more than four different languages with hints
   TrimCLDLangPriors(4, &lang_priors);
 
+  if (applied != NULL) {
+    for (int i = 0; i < GetCLDLangPriorCount(&lang_priors); ++i) {
+      AppliedLangPrior a;
+      a.prior = lang_priors.prior[i];
+      a.sources = (*sources)[GetCLDPriorLang(a.prior)];
+      applied->push_back(a);
+    }
+  }
+
   if (scoringcontext->flags_cld2_html) {
     string print_temp = DumpCLDLangPriors(&lang_priors);
     if (!print_temp.empty()) {
@@ -1635,11 +1729,28 @@ void ApplyHints(const char* buffer,
     Language lang = GetCLDPriorLang(lang_priors.prior[i]);
     int qprob = GetCLDPriorWeight(lang_priors.prior[i]);
     if (qprob > 0) {
-      uint32 langprob = MakeLangProb(lang, qprob);
+      // MakeLangProb takes at most kMaxLangPriorQprob
+      uint32 langprob = MakeLangProb(lang, minint(qprob, kMaxLangPriorQprob));
       AddLangPriorBoost(lang, langprob, scoringcontext);
     }
   }
 
+  // Rule out languages given negative weights by the caller, whatever
+  // the other hints gave them. Negative weights from the other hints only
+  // steer close-set whacks below.
+  if (cld_hints != NULL) {
+    for (int i = 0; i < GetCLDLangPriorCount(&lang_priors); ++i) {
+      Language lang = GetCLDPriorLang(lang_priors.prior[i]);
+      for (int j = 0; j < cld_hints->num_lang_priors; ++j) {
+        OneCLDLangPrior olp = cld_hints->lang_priors[j];
+        if ((GetCLDPriorLang(olp) == lang) && (GetCLDPriorWeight(olp) < 0)) {
+          AddOneWhack(lang, lang, scoringcontext);
+          break;
+        }
+      }
+    }
+  }
+
   // Put whacks into scoring context
   // We do not in general want zh-Hans and zh-Hant to be close pairs,
   // but we do here. Use close_set_count[kCloseSetSize] to count zh, zh-Hant
@@ -1693,6 +1804,37 @@ Language DetectLanguageSummaryV2(
                         ResultChunkVector* resultchunkvector,
                         int* text_bytes,
                         bool* is_reliable) {
+  return DetectLanguageSummaryV2(buffer, buffer_length, is_plain_text,
+                                 cld_hints, allow_extended_lang, flags,
+                                 plus_one, language3, percent3,
+                                 normalized_score3, resultchunkvector,
+                                 text_bytes, is_reliable, NULL);
+}
+
+// Same as above, also filling in extras if not NULL
+Language DetectLanguageSummaryV2(
+                        const char* buffer,
+                        int buffer_length,
+                        bool is_plain_text,
+                        const CLDHints* cld_hints,
+                        bool allow_extended_lang,
+                        int flags,
+                        Language plus_one,
+                        Language* language3,
+                        int* percent3,
+                        double* normalized_score3,
+                        ResultChunkVector* resultchunkvector,
+                        int* text_bytes,
+                        bool* is_reliable,
+                        SummaryExtras* extras) {
+  if (extras != NULL) {
+    extras->reliable_percent3[0] = 0;
+    extras->reliable_percent3[1] = 0;
+    extras->reliable_percent3[2] = 0;
+    extras->closepairs.clear();
+    extras->priors.clear();
+    extras->chunks.clear();
+  }
   language3[0] = UNKNOWN_LANGUAGE;
   language3[1] = UNKNOWN_LANGUAGE;
   language3[2] = UNKNOWN_LANGUAGE;
@@ -1751,13 +1893,17 @@ Language DetectLanguageSummaryV2(
   scoringcontext.ulscript = ULScript_Common;
   scoringcontext.scoringtables = &kScoringtables;
   scoringcontext.scanner = NULL;
+  scoringcontext.chunkreliability = extras != NULL ? &extras->chunks : NULL;
   scoringcontext.init();            // Clear the internal memory arrays
 
   // Now thread safe.
   bool FLAGS_cld2_html = ((flags & kCLDFlagHtml) != 0);
   bool FLAGS_cld2_quiet = ((flags & kCLDFlagQuiet) != 0);
 
-  ApplyHints(buffer, buffer_length, is_plain_text, cld_hints, &scoringcontext);
+  // ApplyHints looks for lang= tags only in HTML
+  bool no_lang_tags = is_plain_text || (flags & kCLDFlagNoLangTags) != 0;
+  ApplyHints(buffer, buffer_length, no_lang_tags, cld_hints, &scoringcontext,
+             extras != NULL ? &extras->priors : NULL);
 
   // Four individual script totals, Latin, Han, other2, other3
   int next_other_tote = 2;
@@ -1872,7 +2018,8 @@ Language DetectLanguageSummaryV2(
                             normalized_score3,
                             resultchunkvector,
                             text_bytes,
-                            is_reliable);
+                            is_reliable,
+                            extras);
         }
       }
     }
@@ -1936,6 +2083,7 @@ Language DetectLanguageSummaryV2(
   // Force close pairs to one or the other
   // If given, also update resultchunkvector
   RefineScoredClosePairs(&doc_tote, resultchunkvector,
+                         extras != NULL ? &extras->closepairs : NULL,
                          FLAGS_cld2_html, FLAGS_cld2_quiet);
 
 
@@ -1986,6 +2134,12 @@ Language DetectLanguageSummaryV2(
                     &summary_lang, is_reliable,
                     FLAGS_cld2_html, FLAGS_cld2_quiet);
 
+    if (extras != NULL) {
+      extras->reliable_percent3[0] = reliable_percent3[0];
+      extras->reliable_percent3[1] = reliable_percent3[1];
+      extras->reliable_percent3[2] = reliable_percent3[2];
+    }
+
     if (FLAGS_cld2_html && !FLAGS_cld2_quiet) {
       for (int i = 0; i < 3; ++i) {
         if (language3[i] != UNKNOWN_LANGUAGE) {
@@ -2051,7 +2205,8 @@ Language DetectLanguageSummaryV2(
                         normalized_score3,
                         resultchunkvector,
                         text_bytes,
-                        is_reliable);
+                        is_reliable,
+                        extras);
   }
 
   // Longer text: Recursive call with top40 set
@@ -2074,7 +2229,8 @@ Language DetectLanguageSummaryV2(
                         normalized_score3,
                         resultchunkvector,
                         text_bytes,
-                        is_reliable);
+                        is_reliable,
+                        extras);
 }
 
 
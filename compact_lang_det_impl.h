@@ -24,6 +24,7 @@
 #include <vector>
 
 #include "compact_lang_det.h"   // For CLDHints, ResultChunkVector
+#include "compact_lang_det_hint_code.h"   // For OneCLDLangPrior
 #include "integral_types.h"
 #include "lang_script.h"
 
@@ -173,6 +174,71 @@ At the end of the first pass --
                         int* text_bytes,
                         bool* is_reliable);
 
+  // A language of a close set whose bytes were moved to another language
+  // of the set, with both byte counts and scores from before the move
+  typedef struct {
+    Language from_lang;
+    Language to_lang;
+    int from_bytes;
+    int to_bytes;
+    int from_score;
+    int to_score;
+  } ClosePairMerge;
+
+  // Where a language prior came from, as bits of AppliedLangPrior.sources
+  static const int kPriorSourceLangTags = 1;      // HTML lang= attributes
+  static const int kPriorSourceContentLang = 2;   // content_language_hint
+  static const int kPriorSourceTLD = 4;           // tld_hint
+  static const int kPriorSourceEncoding = 8;      // encoding_hint
+  static const int kPriorSourceLanguage = 16;     // language_hint
+  static const int kPriorSourceCustom = 32;       // lang_priors
+
+  // A language prior applied to scoring, after merging all hints and
+  // trimming to four languages
+  typedef struct {
+    OneCLDLangPrior prior;
+    int sources;                // kPriorSource bits
+  } AppliedLangPrior;
+
+  // How reliably one chunk of text was scored, as in ChunkSummary
+  typedef struct {
+    int offset;                 // Starting byte offset in original buffer
+    int bytes;                  // Number of bytes in chunk
+    int reliability_delta;      // 0..100, from the margin of lang1 over lang2
+    int reliability_score;      // 0..100, from the score of lang1 per KB
+  } ChunkReliability;
+
+  // Results of DetectLanguageSummaryV2 beyond the summary
+  typedef struct {
+    // Reliability 0..100 of each of language3: the byte-weighted minimum
+    // of reliability_delta and reliability_score over the chunks scored as
+    // that language
+    int reliable_percent3[3];
+    // Close-set languages merged into others, in order
+    std::vector<ClosePairMerge> closepairs;
+    // Language priors applied, from hints and HTML lang= attributes
+    std::vector<AppliedLangPrior> priors;
+    // Each chunk scored, in order
+    std::vector<ChunkReliability> chunks;
+  } SummaryExtras;
+
+  // Same as above, and also fills in extras, which may be NULL.
+  Language DetectLanguageSummaryV2(
+                        const char* buffer,
+                        int buffer_length,
+                        bool is_plain_text,
+                        const CLDHints* cld_hints,
+                        bool allow_extended_lang,
+                        int flags,
+                        Language plus_one,
+                        Language* language3,
+                        int* percent3,
+                        double* normalized_score3,
+                        ResultChunkVector* resultchunkvector,
+                        int* text_bytes,
+                        bool* is_reliable,
+                        SummaryExtras* extras);
+
   // For unit testing:
   // Remove portions of text that have a high density of spaces, or that are
   // overly repetitive, squeezing the remaining text in-place to the front
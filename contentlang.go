@@ -0,0 +1,55 @@
+package cld2
+
+import (
+	"sort"
+	"strings"
+)
+
+// deprecatedCodes are the codes CLD2 still uses for languages whose ISO
+// 639 code has changed, and the codes BCP 47 wants instead.
+var deprecatedCodes = map[string]string{
+	"iw": "he",
+	"jw": "jv",
+	"in": "id",
+	"ji": "yi",
+	"mo": "ro",
+}
+
+// bcp47 returns the BCP 47 tag of l, or "" if l is not a language:
+// UNKNOWN_LANGUAGE, a script-only result such as X_Latin, or one of
+// CLD2's joke languages such as X_PIG_LATIN.
+func bcp47(l Language) string {
+	switch {
+	case l == UNKNOWN_LANGUAGE, l == TG_UNKNOWN_LANGUAGE, l >= NUM_LANGUAGES:
+		return ""
+	case l >= X_Common, l >= X_BORK_BORK_BORK && l != X_KLINGON:
+		return ""
+	}
+	code := l.Code()
+	if c, ok := deprecatedCodes[code]; ok {
+		return c
+	}
+	return code
+}
+
+// ContentLanguage returns a Content-Language header value for a page
+// detected as res: the BCP 47 tags of the estimates with at least
+// minPercent of the text, largest share first, such as "de, en".
+// Results that name no language, such as UNKNOWN_LANGUAGE or script-only
+// results, are left out, and "" is returned if nothing is left. The
+// value can be passed back as Hints.ContentLanguage.
+func ContentLanguage(res Languages, minPercent int) string {
+	est := append([]Estimate(nil), res.Estimates...)
+	sort.SliceStable(est, func(i, j int) bool { return est[i].Percent > est[j].Percent })
+	var tags []string
+	seen := make(map[string]bool)
+	for _, e := range est {
+		tag := bcp47(e.Language)
+		if tag == "" || e.Percent < minPercent || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	return strings.Join(tags, ", ")
+}
@@ -0,0 +1,23 @@
+package cld2
+
+import "testing"
+
+func TestContentLanguage(t *testing.T) {
+	for _, tc := range []struct {
+		est  []Estimate
+		min  int
+		want string
+	}{
+		{[]Estimate{{Language: GERMAN, Percent: 70}, {Language: ENGLISH, Percent: 25}, {Language: FRENCH, Percent: 5}}, 10, "de, en"},
+		{[]Estimate{{Language: ENGLISH, Percent: 20}, {Language: CHINESE_T, Percent: 80}}, 0, "zh-Hant, en"},
+		{[]Estimate{{Language: HEBREW, Percent: 60}, {Language: JAVANESE, Percent: 40}}, 0, "he, jv"},
+		{[]Estimate{{Language: MONTENEGRIN, Percent: 100}}, 50, "sr-ME"},
+		{[]Estimate{{Language: X_Latin, Percent: 90}, {Language: UNKNOWN_LANGUAGE, Percent: 10}}, 0, ""},
+		{[]Estimate{{Language: X_PIG_LATIN, Percent: 50}, {Language: X_KLINGON, Percent: 50}}, 0, "tlh"},
+		{nil, 0, ""},
+	} {
+		if got := ContentLanguage(Languages{Estimates: tc.est}, tc.min); got != tc.want {
+			t.Errorf("%+v: want %q, got %q", tc.est, tc.want, got)
+		}
+	}
+}
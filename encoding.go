@@ -0,0 +1,302 @@
+package cld2
+
+import "strings"
+
+// Encoding is a character encoding as known to CLD2.
+// Detection works on UTF-8 only; other encodings are used as hints,
+// and DetectEncoded converts the common ones to UTF-8 first.
+type Encoding int
+
+// Copied from "encodings.h"
+const (
+	ISO_8859_1           Encoding = 0 // ASCII
+	ISO_8859_2           Encoding = 1 // Latin2
+	ISO_8859_3           Encoding = 2
+	ISO_8859_4           Encoding = 3 // Latin4
+	ISO_8859_5           Encoding = 4 // ISO-8859-5
+	ISO_8859_6           Encoding = 5 // Arabic
+	ISO_8859_7           Encoding = 6 // Greek
+	ISO_8859_8           Encoding = 7 // Hebrew
+	ISO_8859_9           Encoding = 8
+	ISO_8859_10          Encoding = 9
+	JAPANESE_EUC_JP      Encoding = 10 // EUC_JP
+	JAPANESE_SHIFT_JIS   Encoding = 11 // SJS
+	JAPANESE_JIS         Encoding = 12 // JIS
+	CHINESE_BIG5         Encoding = 13 // BIG5
+	CHINESE_GB           Encoding = 14 // GB
+	CHINESE_EUC_CN       Encoding = 15 // Misnamed. Should be EUC_TW.
+	KOREAN_EUC_KR        Encoding = 16 // KSC
+	UNICODE_UNUSED       Encoding = 17 // Unicode
+	CHINESE_EUC_DEC      Encoding = 18 // Misnamed. Should be EUC_TW.
+	CHINESE_CNS          Encoding = 19 // Misnamed. Should be EUC_TW.
+	CHINESE_BIG5_CP950   Encoding = 20 // BIG5_CP950
+	JAPANESE_CP932       Encoding = 21 // CP932
+	UTF8                 Encoding = 22
+	UNKNOWN_ENCODING     Encoding = 23
+	ASCII_7BIT           Encoding = 24 // ISO_8859_1 with all characters <= 127.
+	RUSSIAN_KOI8_R       Encoding = 25 // KOI8R
+	RUSSIAN_CP1251       Encoding = 26 // CP1251
+	MSFT_CP1252          Encoding = 27 // CP1252 aka MSFT euro ascii
+	RUSSIAN_KOI8_RU      Encoding = 28 // CP21866 aka KOI8-U, used for Ukrainian.
+	MSFT_CP1250          Encoding = 29 // CP1250 aka MSFT eastern european
+	ISO_8859_15          Encoding = 30 // aka ISO_8859_0 aka ISO_8859_1 euroized
+	MSFT_CP1254          Encoding = 31 // used for Turkish
+	MSFT_CP1257          Encoding = 32 // used in Baltic countries
+	ISO_8859_11          Encoding = 33 // aka TIS-620, used for Thai
+	MSFT_CP874           Encoding = 34 // used for Thai
+	MSFT_CP1256          Encoding = 35 // used for Arabic
+	MSFT_CP1255          Encoding = 36 // Logical Hebrew Microsoft
+	ISO_8859_8_I         Encoding = 37 // Iso Hebrew Logical
+	HEBREW_VISUAL        Encoding = 38 // Iso Hebrew Visual
+	CZECH_CP852          Encoding = 39
+	CZECH_CSN_369103     Encoding = 40 // aka ISO_IR_139 aka KOI8_CS
+	MSFT_CP1253          Encoding = 41 // used for Greek
+	RUSSIAN_CP866        Encoding = 42
+	ISO_8859_13          Encoding = 43
+	ISO_2022_KR          Encoding = 44
+	GBK                  Encoding = 45
+	GB18030              Encoding = 46
+	BIG5_HKSCS           Encoding = 47
+	ISO_2022_CN          Encoding = 48
+	TSCII                Encoding = 49
+	TAMIL_MONO           Encoding = 50
+	TAMIL_BI             Encoding = 51
+	JAGRAN               Encoding = 52
+	MACINTOSH_ROMAN      Encoding = 53
+	UTF7                 Encoding = 54
+	BHASKAR              Encoding = 55 // Indic encoding - Devanagari
+	HTCHANAKYA           Encoding = 56 // Indic encoding - Devanagari
+	UTF16BE              Encoding = 57 // big-endian UTF-16
+	UTF16LE              Encoding = 58 // little-endian UTF-16
+	UTF32BE              Encoding = 59 // big-endian UTF-32
+	UTF32LE              Encoding = 60 // little-endian UTF-32
+	BINARYENC            Encoding = 61
+	HZ_GB_2312           Encoding = 62
+	UTF8UTF8             Encoding = 63
+	TAM_ELANGO           Encoding = 64 // Elango - Tamil
+	TAM_LTTMBARANI       Encoding = 65 // Barani - Tamil
+	TAM_SHREE            Encoding = 66 // Shree - Tamil
+	TAM_TBOOMIS          Encoding = 67 // TBoomis - Tamil
+	TAM_TMNEWS           Encoding = 68 // TMNews - Tamil
+	TAM_WEBTAMIL         Encoding = 69 // Webtamil - Tamil
+	KDDI_SHIFT_JIS       Encoding = 70
+	DOCOMO_SHIFT_JIS     Encoding = 71
+	SOFTBANK_SHIFT_JIS   Encoding = 72
+	KDDI_ISO_2022_JP     Encoding = 73
+	SOFTBANK_ISO_2022_JP Encoding = 74
+	NUM_ENCODINGS        Encoding = 75 // not a valid Encoding
+)
+
+var encodingToName = []string{
+	"ISO_8859_1",
+	"ISO_8859_2",
+	"ISO_8859_3",
+	"ISO_8859_4",
+	"ISO_8859_5",
+	"ISO_8859_6",
+	"ISO_8859_7",
+	"ISO_8859_8",
+	"ISO_8859_9",
+	"ISO_8859_10",
+	"JAPANESE_EUC_JP",
+	"JAPANESE_SHIFT_JIS",
+	"JAPANESE_JIS",
+	"CHINESE_BIG5",
+	"CHINESE_GB",
+	"CHINESE_EUC_CN",
+	"KOREAN_EUC_KR",
+	"UNICODE_UNUSED",
+	"CHINESE_EUC_DEC",
+	"CHINESE_CNS",
+	"CHINESE_BIG5_CP950",
+	"JAPANESE_CP932",
+	"UTF8",
+	"UNKNOWN_ENCODING",
+	"ASCII_7BIT",
+	"RUSSIAN_KOI8_R",
+	"RUSSIAN_CP1251",
+	"MSFT_CP1252",
+	"RUSSIAN_KOI8_RU",
+	"MSFT_CP1250",
+	"ISO_8859_15",
+	"MSFT_CP1254",
+	"MSFT_CP1257",
+	"ISO_8859_11",
+	"MSFT_CP874",
+	"MSFT_CP1256",
+	"MSFT_CP1255",
+	"ISO_8859_8_I",
+	"HEBREW_VISUAL",
+	"CZECH_CP852",
+	"CZECH_CSN_369103",
+	"MSFT_CP1253",
+	"RUSSIAN_CP866",
+	"ISO_8859_13",
+	"ISO_2022_KR",
+	"GBK",
+	"GB18030",
+	"BIG5_HKSCS",
+	"ISO_2022_CN",
+	"TSCII",
+	"TAMIL_MONO",
+	"TAMIL_BI",
+	"JAGRAN",
+	"MACINTOSH_ROMAN",
+	"UTF7",
+	"BHASKAR",
+	"HTCHANAKYA",
+	"UTF16BE",
+	"UTF16LE",
+	"UTF32BE",
+	"UTF32LE",
+	"BINARYENC",
+	"HZ_GB_2312",
+	"UTF8UTF8",
+	"TAM_ELANGO",
+	"TAM_LTTMBARANI",
+	"TAM_SHREE",
+	"TAM_TBOOMIS",
+	"TAM_TMNEWS",
+	"TAM_WEBTAMIL",
+	"KDDI_SHIFT_JIS",
+	"DOCOMO_SHIFT_JIS",
+	"SOFTBANK_SHIFT_JIS",
+	"KDDI_ISO_2022_JP",
+	"SOFTBANK_ISO_2022_JP",
+}
+
+// String returns the CLD2 name of the encoding, e.g. "JAPANESE_SHIFT_JIS".
+func (e Encoding) String() string {
+	if e < 0 || e >= NUM_ENCODINGS {
+		return "UNKNOWN_ENCODING"
+	}
+	return encodingToName[e]
+}
+
+// charsetToEncoding maps lowercased IANA charset names and
+// common aliases to encodings.
+var charsetToEncoding = map[string]Encoding{
+	"us-ascii":          ASCII_7BIT,
+	"ascii":             ASCII_7BIT,
+	"iso-8859-1":        ISO_8859_1,
+	"iso_8859-1":        ISO_8859_1,
+	"iso8859-1":         ISO_8859_1,
+	"latin1":            ISO_8859_1,
+	"l1":                ISO_8859_1,
+	"iso-8859-2":        ISO_8859_2,
+	"iso_8859-2":        ISO_8859_2,
+	"latin2":            ISO_8859_2,
+	"iso-8859-3":        ISO_8859_3,
+	"latin3":            ISO_8859_3,
+	"iso-8859-4":        ISO_8859_4,
+	"latin4":            ISO_8859_4,
+	"iso-8859-5":        ISO_8859_5,
+	"cyrillic":          ISO_8859_5,
+	"iso-8859-6":        ISO_8859_6,
+	"arabic":            ISO_8859_6,
+	"iso-8859-7":        ISO_8859_7,
+	"greek":             ISO_8859_7,
+	"iso-8859-8":        ISO_8859_8,
+	"hebrew":            ISO_8859_8,
+	"visual":            HEBREW_VISUAL,
+	"iso-8859-8-i":      ISO_8859_8_I,
+	"logical":           ISO_8859_8_I,
+	"iso-8859-9":        ISO_8859_9,
+	"latin5":            ISO_8859_9,
+	"iso-8859-10":       ISO_8859_10,
+	"latin6":            ISO_8859_10,
+	"iso-8859-11":       ISO_8859_11,
+	"tis-620":           ISO_8859_11,
+	"iso-8859-13":       ISO_8859_13,
+	"iso-8859-15":       ISO_8859_15,
+	"latin-9":           ISO_8859_15,
+	"latin9":            ISO_8859_15,
+	"euc-jp":            JAPANESE_EUC_JP,
+	"x-euc-jp":          JAPANESE_EUC_JP,
+	"shift_jis":         JAPANESE_SHIFT_JIS,
+	"shift-jis":         JAPANESE_SHIFT_JIS,
+	"sjis":              JAPANESE_SHIFT_JIS,
+	"x-sjis":            JAPANESE_SHIFT_JIS,
+	"ms_kanji":          JAPANESE_SHIFT_JIS,
+	"csshiftjis":        JAPANESE_SHIFT_JIS,
+	"windows-31j":       JAPANESE_CP932,
+	"cp932":             JAPANESE_CP932,
+	"iso-2022-jp":       JAPANESE_JIS,
+	"csiso2022jp":       JAPANESE_JIS,
+	"big5":              CHINESE_BIG5,
+	"csbig5":            CHINESE_BIG5,
+	"x-x-big5":          CHINESE_BIG5,
+	"cp950":             CHINESE_BIG5_CP950,
+	"big5-hkscs":        BIG5_HKSCS,
+	"gb2312":            CHINESE_GB,
+	"csgb2312":          CHINESE_GB,
+	"euc-cn":            CHINESE_GB,
+	"gbk":               GBK,
+	"cp936":             GBK,
+	"windows-936":       GBK,
+	"x-gbk":             GBK,
+	"gb18030":           GB18030,
+	"hz-gb-2312":        HZ_GB_2312,
+	"euc-tw":            CHINESE_EUC_CN,
+	"x-euc-tw":          CHINESE_EUC_CN,
+	"euc-kr":            KOREAN_EUC_KR,
+	"cseuckr":           KOREAN_EUC_KR,
+	"ks_c_5601-1987":    KOREAN_EUC_KR,
+	"windows-949":       KOREAN_EUC_KR,
+	"cp949":             KOREAN_EUC_KR,
+	"iso-2022-kr":       ISO_2022_KR,
+	"iso-2022-cn":       ISO_2022_CN,
+	"utf-8":             UTF8,
+	"utf8":              UTF8,
+	"unicode-1-1-utf-8": UTF8,
+	"utf-7":             UTF7,
+	"utf-16":            UTF16LE, // or as its byte order mark says
+	"utf-16le":          UTF16LE,
+	"utf-16be":          UTF16BE,
+	"utf-32":            UTF32LE,
+	"utf-32le":          UTF32LE,
+	"utf-32be":          UTF32BE,
+	"koi8-r":            RUSSIAN_KOI8_R,
+	"koi8":              RUSSIAN_KOI8_R,
+	"cskoi8r":           RUSSIAN_KOI8_R,
+	"koi8-u":            RUSSIAN_KOI8_RU,
+	"windows-1250":      MSFT_CP1250,
+	"cp1250":            MSFT_CP1250,
+	"windows-1251":      RUSSIAN_CP1251,
+	"cp1251":            RUSSIAN_CP1251,
+	"windows-1252":      MSFT_CP1252,
+	"cp1252":            MSFT_CP1252,
+	"windows-1253":      MSFT_CP1253,
+	"cp1253":            MSFT_CP1253,
+	"windows-1254":      MSFT_CP1254,
+	"cp1254":            MSFT_CP1254,
+	"windows-1255":      MSFT_CP1255,
+	"cp1255":            MSFT_CP1255,
+	"windows-1256":      MSFT_CP1256,
+	"cp1256":            MSFT_CP1256,
+	"windows-1257":      MSFT_CP1257,
+	"cp1257":            MSFT_CP1257,
+	"windows-874":       MSFT_CP874,
+	"cp874":             MSFT_CP874,
+	"ibm852":            CZECH_CP852,
+	"cp852":             CZECH_CP852,
+	"iso-ir-139":        CZECH_CSN_369103,
+	"ibm866":            RUSSIAN_CP866,
+	"cp866":             RUSSIAN_CP866,
+	"macintosh":         MACINTOSH_ROMAN,
+	"mac":               MACINTOSH_ROMAN,
+	"x-mac-roman":       MACINTOSH_ROMAN,
+	"tscii":             TSCII,
+}
+
+// ParseEncoding returns the encoding for an IANA charset name such as
+// "Shift_JIS" or "windows-1251", as found in Content-Type headers and
+// <meta charset> tags. Names are matched case-insensitively.
+// Returns UNKNOWN_ENCODING if the name isn't known.
+func ParseEncoding(charset string) Encoding {
+	charset = strings.ToLower(strings.Trim(charset, " \t\"'"))
+	if e, ok := charsetToEncoding[charset]; ok {
+		return e
+	}
+	return UNKNOWN_ENCODING
+}
@@ -0,0 +1,81 @@
+//go:build !cld2_disable && cgo
+// +build !cld2_disable,cgo
+
+package cld2
+
+// #include <stdlib.h>
+// #include "cld2.h"
+import "C"
+import (
+	"unsafe"
+)
+
+// ExtractText returns the text CLD2 scores in an HTML document.
+// Tags, comments, scripts and styles are removed and entities are
+// expanded. Tags become a space, or a newline for <p>, <br> and <tr>,
+// and runs of spaces are squeezed. Each chunk of text starts with a
+// space. The offset map maps byte offsets in text back to html.
+func ExtractText(html []byte) (text []byte, offsets OffsetMap) {
+	return extract(html, false, false)
+}
+
+// ExtractLetters returns the letters CLD2 scores in text, lowercased,
+// in runs of one script separated by spaces. The offset map maps byte
+// offsets in letters back to text.
+func ExtractLetters(text []byte, html bool) (letters []byte, offsets OffsetMap) {
+	return extract(text, !html, true)
+}
+
+// extract runs the CLD2 script scanner over data.
+func extract(data []byte, plain, letters bool) ([]byte, OffsetMap) {
+	cs := cBytes(data)
+	defer C.free(unsafe.Pointer(cs))
+
+	var isPlain, isLetters C.char
+	if plain {
+		isPlain = 1
+	}
+	if letters {
+		isLetters = 1
+	}
+	var dst C.struct__extracted
+	C.ExtractText(&dst, cs, C.int(len(data)), isPlain, isLetters)
+	defer C.free(unsafe.Pointer(dst.text))
+	defer C.free(unsafe.Pointer(dst.offsets))
+
+	n := int(dst.text_bytes)
+	text := C.GoBytes(unsafe.Pointer(dst.text), dst.text_bytes)
+	pos := make([]int, n+1)
+	for i, p := range unsafe.Slice((*C.int)(dst.offsets), n+1) {
+		pos[i] = int(p)
+	}
+	return text, offsetMapFromPositions(pos)
+}
+
+// defaultLangTagScan is how much HTML CLD2 looks at for lang attributes,
+// FLAGS_cld_max_lang_tag_scan_kb.
+const defaultLangTagScan = 8 << 10
+
+// DeclaredLanguages returns the languages declared in the first maxScan
+// bytes of html, or 8 KB if maxScan <= 0, in the order declared. It
+// reads lang and xml:lang attributes and content-language and language
+// meta tags as GetLangTagsFromHtml does, and understands the tags and
+// language names CLD2 uses for hints, such as "en-GB" or "Srpski".
+// Like CLD2, it ignores pages that declare more than five tags, which
+// are usually language menus.
+func DeclaredLanguages(html []byte, maxScan int) []Language {
+	if maxScan <= 0 {
+		maxScan = defaultLangTagScan
+	}
+	cs := cBytes(html)
+	defer C.free(unsafe.Pointer(cs))
+
+	// Each of the five tags can name two languages
+	var langs [10]C.int
+	n := C.DeclaredLanguages(cs, C.int(len(html)), C.int(maxScan), &langs[0], C.int(len(langs)))
+	var out []Language
+	for _, l := range langs[:n] {
+		out = append(out, Language(l))
+	}
+	return out
+}
@@ -0,0 +1,171 @@
+//go:build ignore
+// +build ignore
+
+// This program generates charset_tables.go from the WHATWG encoding
+// indexes at https://encoding.spec.whatwg.org/. Run it with
+// "go generate", or pass -dir to read previously downloaded
+// index-*.txt files instead.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+var dir = flag.String("dir", "", "directory holding the index-*.txt files")
+
+// singleByte lists the single-byte indexes and the Go table names.
+var singleByte = []struct{ index, table string }{
+	{"iso-8859-2", "iso8859_2"},
+	{"iso-8859-3", "iso8859_3"},
+	{"iso-8859-4", "iso8859_4"},
+	{"iso-8859-5", "iso8859_5"},
+	{"iso-8859-6", "iso8859_6"},
+	{"iso-8859-7", "iso8859_7"},
+	{"iso-8859-8", "iso8859_8"},
+	{"iso-8859-10", "iso8859_10"},
+	{"iso-8859-13", "iso8859_13"},
+	{"iso-8859-15", "iso8859_15"},
+	{"koi8-r", "koi8r"},
+	{"koi8-u", "koi8u"},
+	{"ibm866", "ibm866"},
+	{"macintosh", "macintosh"},
+	{"windows-874", "windows874"},
+	{"windows-1250", "windows1250"},
+	{"windows-1251", "windows1251"},
+	{"windows-1252", "windows1252"},
+	{"windows-1253", "windows1253"},
+	{"windows-1254", "windows1254"},
+	{"windows-1255", "windows1255"},
+	{"windows-1256", "windows1256"},
+	{"windows-1257", "windows1257"},
+}
+
+// doubleByte lists the pointer indexes of the CJK encodings.
+var doubleByte = []struct{ index, table, typ string }{
+	{"jis0208", "jis0208", "uint16"},
+	{"jis0212", "jis0212", "uint16"},
+	{"big5", "big5", "rune"},
+	{"gb18030", "gb18030", "uint16"},
+	{"euc-kr", "euckr", "uint16"},
+}
+
+// open returns the named index, from -dir or from the web.
+func open(name string) io.ReadCloser {
+	file := "index-" + name + ".txt"
+	if *dir != "" {
+		f, err := os.Open(filepath.Join(*dir, file))
+		if err != nil {
+			log.Fatal(err)
+		}
+		return f
+	}
+	res, err := http.Get("https://encoding.spec.whatwg.org/" + file)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if res.StatusCode != http.StatusOK {
+		log.Fatalf("%s: %s", file, res.Status)
+	}
+	return res.Body
+}
+
+// readIndex returns the pointer to code point pairs of an index.
+func readIndex(name string) map[int]rune {
+	r := open(name)
+	defer r.Close()
+	m := make(map[int]rune)
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		var p int
+		var c rune
+		if _, err := fmt.Sscanf(line, "%d 0x%x", &p, &c); err != nil {
+			log.Fatalf("%s: cannot parse %q", name, line)
+		}
+		m[p] = c
+	}
+	if err := sc.Err(); err != nil {
+		log.Fatal(err)
+	}
+	return m
+}
+
+// writeTable writes the dense table name, with zero for unmapped pointers.
+func writeTable(buf *bytes.Buffer, name, typ string, size int, m map[int]rune) {
+	fmt.Fprintf(buf, "var %s = [%d]%s{", name, size, typ)
+	for p := 0; p < size; p++ {
+		if p%12 == 0 {
+			fmt.Fprintf(buf, "\n")
+		}
+		fmt.Fprintf(buf, "0x%04X, ", m[p])
+	}
+	fmt.Fprintf(buf, "\n}\n\n")
+}
+
+func main() {
+	flag.Parse()
+
+	var buf bytes.Buffer
+	fmt.Fprintln(&buf, "// Code generated by gen_charsets.go; DO NOT EDIT.")
+	fmt.Fprintln(&buf)
+	fmt.Fprintln(&buf, "package cld2")
+	fmt.Fprintln(&buf)
+	fmt.Fprintln(&buf, "// Single-byte code pages, subscripted by byte - 0x80.")
+	fmt.Fprintln(&buf)
+	for _, sb := range singleByte {
+		fmt.Fprintf(&buf, "// %s is from index-%s.txt.\n", sb.table, sb.index)
+		writeTable(&buf, sb.table, "uint16", 128, readIndex(sb.index))
+	}
+
+	fmt.Fprintln(&buf, "// Double-byte indexes, subscripted by WHATWG pointer.")
+	fmt.Fprintln(&buf)
+	for _, db := range doubleByte {
+		m := readIndex(db.index)
+		size := 0
+		for p := range m {
+			if p >= size {
+				size = p + 1
+			}
+		}
+		fmt.Fprintf(&buf, "// %s is from index-%s.txt.\n", db.table, db.index)
+		writeTable(&buf, db.table, db.typ, size, m)
+	}
+
+	// The gb18030 ranges are sorted by pointer.
+	ranges := readIndex("gb18030-ranges")
+	var ptrs []int
+	for p := range ranges {
+		ptrs = append(ptrs, p)
+	}
+	sort.Ints(ptrs)
+	fmt.Fprintln(&buf, "// gb18030Ranges is from index-gb18030-ranges.txt: four-byte")
+	fmt.Fprintln(&buf, "// pointer and first code point of each range.")
+	fmt.Fprintf(&buf, "var gb18030Ranges = [%d][2]uint16{\n", len(ptrs))
+	for _, p := range ptrs {
+		fmt.Fprintf(&buf, "{%d, 0x%04X},\n", p, ranges[p])
+	}
+	fmt.Fprintln(&buf, "}")
+
+	out, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile("charset_tables.go", out, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
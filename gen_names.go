@@ -0,0 +1,238 @@
+//go:build ignore
+// +build ignore
+
+// This program generates names_table.go from the CLD2 language tables
+// in generated_language.cc and the curated names in language_names.tsv.
+// It also copies the codes CLD2 accepts for each language, aliases
+// included, from kCodeToLanguage.
+// Run it with "go generate".
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// entryRe matches one element of a generated_language.cc string table:
+//
+//	"ENGLISH",               // 0 en
+var entryRe = regexp.MustCompile(`^\s*"([^"]*)",\s*// (\d+)`)
+
+// readTable returns the entries of the named string table in src.
+func readTable(src []byte, name string) []string {
+	var out []string
+	in := false
+	sc := bufio.NewScanner(bytes.NewReader(src))
+	for sc.Scan() {
+		line := sc.Text()
+		if strings.Contains(line, name+"[") {
+			in = true
+			continue
+		}
+		if !in {
+			continue
+		}
+		if strings.HasPrefix(line, "};") {
+			break
+		}
+		m := entryRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		if n, _ := strconv.Atoi(m[2]); n != len(out) {
+			log.Fatalf("%s: entry %d out of order", name, n)
+		}
+		out = append(out, m[1])
+	}
+	if len(out) == 0 {
+		log.Fatalf("table %s not found", name)
+	}
+	return out
+}
+
+// pairRe matches one element of the kCodeToLanguage table:
+//
+//	{"he",     6},  // he
+var pairRe = regexp.MustCompile(`^\s*\{"([^"]*)",\s*(\d+)\},`)
+
+// readCodes returns the entries of kCodeToLanguage in src.
+func readCodes(src []byte) map[string]int {
+	codes := make(map[string]int)
+	in := false
+	sc := bufio.NewScanner(bytes.NewReader(src))
+	for sc.Scan() {
+		line := sc.Text()
+		if strings.Contains(line, "kCodeToLanguage[") {
+			in = true
+			continue
+		}
+		if !in {
+			continue
+		}
+		if strings.HasPrefix(line, "};") {
+			break
+		}
+		if m := pairRe.FindStringSubmatch(line); m != nil {
+			codes[m[1]], _ = strconv.Atoi(m[2])
+		}
+	}
+	if len(codes) == 0 {
+		log.Fatal("table kCodeToLanguage not found")
+	}
+	return codes
+}
+
+// englishName derives a display name from a CLD2 table name:
+// "SCOTS_GAELIC" becomes "Scots Gaelic", "X_Lycian" becomes
+// "Lycian script". Unused slots, named by their number, get no name.
+func englishName(name, code string) string {
+	if _, err := strconv.Atoi(name); err == nil {
+		return ""
+	}
+	name = strings.TrimPrefix(name, "X_")
+	words := strings.Split(name, "_")
+	for i, w := range words {
+		if w == strings.ToUpper(w) {
+			words[i] = w[:1] + strings.ToLower(w[1:])
+		}
+	}
+	name = strings.Join(words, " ")
+	if strings.HasPrefix(code, "xx-") {
+		name += " script"
+	}
+	return name
+}
+
+func main() {
+	src, err := ioutil.ReadFile("generated_language.cc")
+	if err != nil {
+		log.Fatal(err)
+	}
+	names := readTable(src, "kLanguageToName")
+	codes := readTable(src, "kLanguageToCode")
+	if len(names) != len(codes) {
+		log.Fatalf("have %d names but %d codes", len(names), len(codes))
+	}
+	lookup := readCodes(src)
+	byCode := make(map[string]int)
+	english := make([]string, len(names))
+	for i := range names {
+		english[i] = englishName(names[i], codes[i])
+		if english[i] != "" {
+			byCode[codes[i]] = i
+		}
+	}
+
+	f, err := os.Open("language_names.tsv")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+	native := make([]string, len(names))
+	var uiLangs []int
+	display := make(map[int]map[int]string)
+	sc := bufio.NewScanner(f)
+	header := true
+	for sc.Scan() {
+		line := sc.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		cols := strings.Split(line, "\t")
+		if header {
+			for _, code := range cols[3:] {
+				i, ok := byCode[code]
+				if !ok {
+					log.Fatalf("unknown UI language %q", code)
+				}
+				uiLangs = append(uiLangs, i)
+				display[i] = make(map[int]string)
+			}
+			header = false
+			continue
+		}
+		if len(cols) != 3+len(uiLangs) {
+			log.Fatalf("want %d columns: %q", 3+len(uiLangs), line)
+		}
+		i, ok := byCode[cols[0]]
+		if !ok {
+			log.Fatalf("unknown language code %q", cols[0])
+		}
+		if cols[1] != "" {
+			english[i] = cols[1]
+		}
+		native[i] = cols[2]
+		for j, ui := range uiLangs {
+			if cols[3+j] != "" {
+				display[ui][i] = cols[3+j]
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		log.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintln(&buf, "// Code generated by gen_names.go; DO NOT EDIT.")
+	fmt.Fprintln(&buf)
+	fmt.Fprintln(&buf, "package cld2")
+	fmt.Fprintln(&buf)
+	fmt.Fprintln(&buf, "// English display names, subscripted by Language.")
+	fmt.Fprintln(&buf, "var languageToName = []string{")
+	for i, name := range english {
+		fmt.Fprintf(&buf, "%q, // %d %s\n", name, i, codes[i])
+	}
+	fmt.Fprintln(&buf, "}")
+	fmt.Fprintln(&buf)
+	fmt.Fprintln(&buf, "// Names of each language in the language itself, subscripted by Language.")
+	fmt.Fprintln(&buf, "var languageToNativeName = []string{")
+	for i, name := range native {
+		fmt.Fprintf(&buf, "%q, // %d %s\n", name, i, codes[i])
+	}
+	fmt.Fprintln(&buf, "}")
+	fmt.Fprintln(&buf)
+	fmt.Fprintln(&buf, "// Display names keyed by UI language, then by named language.")
+	fmt.Fprintln(&buf, "var displayNames = map[Language]map[Language]string{")
+	for _, ui := range uiLangs {
+		fmt.Fprintf(&buf, "%d: { // %s\n", ui, codes[ui])
+		var langs []int
+		for l := range display[ui] {
+			langs = append(langs, l)
+		}
+		sort.Ints(langs)
+		for _, l := range langs {
+			fmt.Fprintf(&buf, "%d: %q, // %s\n", l, display[ui][l], codes[l])
+		}
+		fmt.Fprintln(&buf, "},")
+	}
+	fmt.Fprintln(&buf, "}")
+	fmt.Fprintln(&buf)
+	fmt.Fprintln(&buf, "// Codes CLD2 accepts for each language, such as \"he\" and \"zh-TW\", from kCodeToLanguage.")
+	fmt.Fprintln(&buf, "var codeLookup = map[string]Language{")
+	var keys []string
+	for code := range lookup {
+		keys = append(keys, code)
+	}
+	sort.Strings(keys)
+	for _, code := range keys {
+		fmt.Fprintf(&buf, "%q: %d, // %s\n", code, lookup[code], codes[lookup[code]])
+	}
+	fmt.Fprintln(&buf, "}")
+
+	out, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile("names_table.go", out, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -699,7 +699,14 @@ bool ScriptScanner::GetOneTextSpan(LangSpan* span) {
   int put = 1;              // Start after the initial space
   int tlen, plen;
 
+  // Build offsets from span->text back to start_byte_ + span->offset,
+  // as GetOneScriptSpan does
+  map2original_.Clear();
+  map2original_.Delete(span->offset);   // So that MapBack(0) gives offset
+  map2original_.Insert(1);              // The initial space
+
   if (byte_length_ <= 0) {
+    map2original_.Reset();
     return false;          // No more text to be found
   }
 
@@ -739,16 +746,28 @@ bool ScriptScanner::GetOneTextSpan(LangSpan* span) {
         if (!last_byte_was_space || !WS(c)) {
           script_buffer_[put++] = c;      // Advance dest
           last_byte_was_space = WS(c);
+          map2original_.Insert(1);        // Maps back to the tag start
         }
+        map2original_.Delete(tlen);
       } else if (c == '>') {
         // Unexpected end of tag; copy it and go around again
         tlen = 1;         // Over the >
         script_buffer_[put++] = c;    // Advance dest
+        map2original_.Copy(1);
       } else if (c == '&') {
         // Expand entity, no advance
         EntityToBuffer(next_byte_ + take, byte_length_ - take,
                        script_buffer_ + put, &tlen, &plen);
         put += plen;                  // Advance dest
+        if (tlen == plen) {
+          map2original_.Copy(tlen);
+        } else if (tlen < plen) {
+          map2original_.Copy(tlen);
+          map2original_.Insert(plen - tlen);
+        } else {    // plen < tlen
+          map2original_.Copy(plen);
+          map2original_.Delete(tlen - plen);
+        }
       }
       take += tlen;                   // Advance source
     } else {
@@ -756,6 +775,9 @@ bool ScriptScanner::GetOneTextSpan(LangSpan* span) {
       if (!last_byte_was_space || !WS(c)) {
         script_buffer_[put++] = c;      // Advance dest
         last_byte_was_space = WS(c);
+        map2original_.Copy(1);
+      } else {
+        map2original_.Delete(1);
       }
       ++take;                         // Advance source
     }
@@ -790,6 +812,10 @@ bool ScriptScanner::GetOneTextSpan(LangSpan* span) {
   script_buffer_[put + 1] = ' ';
   script_buffer_[put + 2] = ' ';
   script_buffer_[put + 3] = '\0';
+  // Bytes backed up over above were copied one for one, so the map
+  // stays correct up to put
+  map2original_.Insert(4);
+  map2original_.Reset();
 
   span->text_bytes = put;       // Does not include the last four chars above
   return true;
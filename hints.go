@@ -0,0 +1,233 @@
+package cld2
+
+import (
+	"math"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Hints carry what is known about a text from outside the text itself,
+// such as HTTP headers or the URL it came from. Passing hints improves
+// accuracy, most of all on short texts.
+type Hints struct {
+	ContentLanguage string   // Content-Language header; "mi,en" boosts Maori and English
+	TLD             string   // lowercase top-level domain; "id" boosts Indonesian
+	Encoding        Encoding // original encoding; JAPANESE_SHIFT_JIS boosts Japanese
+	Language        Language // language known from any other context
+	Priors          *Priors  // weighted priors from any other source, or nil
+}
+
+// NoHints is a Hints value that doesn't steer detection.
+// Build hints from it rather than from the zero value,
+// whose Language is ENGLISH.
+var NoHints = Hints{Encoding: UNKNOWN_ENCODING, Language: UNKNOWN_LANGUAGE}
+
+// acceptLanguageWeight is the prior weight of a language accepted with
+// q=1: 1000 times more likely. Readers' languages say less about a text
+// than its Content-Language, which CLD2 weighs at 10.
+const acceptLanguageWeight = 6
+
+// HintsFromAcceptLanguage returns hints for text from a user whose
+// browser sent header as Accept-Language, such as
+// "fr-CH, fr;q=0.9, en;q=0.8, *;q=0.5". Each language range becomes a
+// prior weighted by its q-value, from 6 for q=1 down to 1. Wildcards,
+// ranges with q=0, ranges CLD2 has no language for and malformed
+// entries are skipped; a language named more than once keeps its
+// highest weight.
+func HintsFromAcceptLanguage(header string) Hints {
+	hints := NoHints
+	weights := make(map[Language]int)
+	var order []Language
+	for _, entry := range strings.Split(header, ",") {
+		lang, q, ok := parseAcceptRange(entry)
+		if !ok || q == 0 {
+			continue
+		}
+		w := int(math.Round(q * acceptLanguageWeight))
+		if w < 1 {
+			w = 1
+		}
+		if old, seen := weights[lang]; !seen {
+			order = append(order, lang)
+		} else if old > w {
+			w = old
+		}
+		weights[lang] = w
+	}
+	if len(order) > 0 {
+		hints.Priors = new(Priors)
+		for _, lang := range order {
+			hints.Priors.Add(lang, weights[lang])
+		}
+	}
+	return hints
+}
+
+// parseAcceptRange parses one entry of an Accept-Language header,
+// "range" or "range;q=value", and returns its language and q-value.
+// It returns false for a wildcard, an unknown language or a malformed
+// entry.
+func parseAcceptRange(entry string) (Language, float64, bool) {
+	params := strings.Split(entry, ";")
+	tag := strings.TrimSpace(params[0])
+	if tag == "" || tag == "*" || !isLanguageRange(tag) {
+		return UNKNOWN_LANGUAGE, 0, false
+	}
+	q := 1.0
+	for _, p := range params[1:] {
+		k, v, ok := strings.Cut(strings.TrimSpace(p), "=")
+		if !ok || !strings.EqualFold(strings.TrimSpace(k), "q") {
+			return UNKNOWN_LANGUAGE, 0, false
+		}
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil || f < 0 || f > 1 {
+			return UNKNOWN_LANGUAGE, 0, false
+		}
+		q = f
+	}
+	lang := LanguageFromTag(tag)
+	return lang, q, lang != UNKNOWN_LANGUAGE
+}
+
+// isLanguageRange reports whether s has the syntax of a language range:
+// subtags of 1 to 8 letters or digits separated by hyphens, the first
+// of letters only.
+func isLanguageRange(s string) bool {
+	for i, sub := range strings.Split(s, "-") {
+		if len(sub) < 1 || len(sub) > 8 {
+			return false
+		}
+		for _, c := range sub {
+			letter := c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+			if !letter && (i == 0 || c < '0' || c > '9') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Prior weights of the parts of a URL: a lang= parameter is usually
+// chosen by the reader, while a locale in the path or host is how the
+// site sorts its pages.
+const (
+	urlQueryWeight     = 8
+	urlPathWeight      = 6
+	urlSubdomainWeight = 4
+)
+
+// urlLangParams are query parameters that name a language.
+var urlLangParams = []string{"lang", "hl", "locale", "language"}
+
+// secondLevelLabels are the labels under a country code that make a
+// public suffix of two labels, as in example.co.uk or example.com.br.
+var secondLevelLabels = map[string]bool{
+	"ac": true, "co": true, "com": true, "edu": true, "go": true,
+	"gob": true, "gov": true, "ltd": true, "mil": true, "ne": true,
+	"net": true, "nic": true, "or": true, "org": true, "plc": true,
+}
+
+// HintsFromURL returns hints for a document fetched from u. The
+// top-level domain becomes the TLD hint, and a language in the first
+// subdomain (de.example.com), the first path segment (/fr/, /pt-br/)
+// or a lang, hl, locale or language query parameter becomes a prior.
+// The query weighs most and the subdomain least. Subdomains and path
+// segments count only if they are clearly locales: a common two-letter
+// code such as "de", alone or with a region or script, or any two-letter
+// code with an uppercase region, such as "it_IT"; "/it/", "/my/" and
+// "/new/" are not languages.
+func HintsFromURL(u *url.URL) Hints {
+	hints := NoHints
+	if u == nil {
+		return hints
+	}
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if labels := strings.Split(host, "."); len(labels) > 1 && net.ParseIP(host) == nil {
+		tld := labels[len(labels)-1]
+		hints.TLD = tld
+		// Labels left of the registrable domain are subdomains
+		n := len(labels) - 2
+		if len(labels) >= 3 && len(tld) == 2 && secondLevelLabels[labels[len(labels)-2]] {
+			n--
+		}
+		if n > 0 {
+			if lang := urlLanguage(labels[0]); lang != UNKNOWN_LANGUAGE {
+				hints.raisePrior(lang, urlSubdomainWeight)
+			}
+		}
+	}
+	segment, _, _ := strings.Cut(strings.TrimPrefix(u.EscapedPath(), "/"), "/")
+	if lang := urlLanguage(segment); lang != UNKNOWN_LANGUAGE {
+		hints.raisePrior(lang, urlPathWeight)
+	}
+	q := u.Query()
+	for _, p := range urlLangParams {
+		if lang := LanguageFromTag(q.Get(p)); lang != UNKNOWN_LANGUAGE {
+			hints.raisePrior(lang, urlQueryWeight)
+			break
+		}
+	}
+	return hints
+}
+
+// urlLocales are the language codes a host label or path segment may
+// be on its own, or with any region. Codes that are also words or
+// abbreviations common in URLs, such as "it", "is", "no", "to", "my",
+// "id", "am", "hr", "uk", "eu" or "ca", are left out: they count only
+// with an uppercase region, as in "it_IT" or "ca-ES".
+var urlLocales = map[string]bool{
+	"ar": true, "bg": true, "bn": true, "cy": true, "da": true,
+	"de": true, "el": true, "en": true, "es": true, "et": true,
+	"fa": true, "fi": true, "fr": true, "ga": true, "he": true,
+	"hu": true, "hy": true, "ja": true, "ka": true, "kk": true,
+	"ko": true, "lt": true, "lv": true, "mk": true, "mn": true,
+	"nb": true, "nl": true, "nn": true, "pl": true, "pt": true,
+	"ro": true, "ru": true, "sk": true, "sl": true, "sq": true,
+	"sr": true, "sv": true, "ta": true, "te": true, "th": true,
+	"tr": true, "ur": true, "uz": true, "vi": true, "zh": true,
+}
+
+// urlLanguage returns the language of a host label or path segment
+// that looks like a locale: a code in urlLocales, optionally followed by
+// a region or script, such as "fr", "pt-br" or "zh-hant", or another
+// two-letter code with an uppercase region, such as "it_IT". Three-letter
+// codes and longer words are not taken as languages, so neither "/new/"
+// nor "/video/" is a language.
+func urlLanguage(s string) Language {
+	s = strings.Replace(s, "_", "-", -1)
+	primary, rest, _ := strings.Cut(s, "-")
+	if len(primary) != 2 || !isLanguageRange(s) {
+		return UNKNOWN_LANGUAGE
+	}
+	switch {
+	case rest == "":
+		if !urlLocales[strings.ToLower(primary)] {
+			return UNKNOWN_LANGUAGE
+		}
+	case len(rest) == 2:
+		if !urlLocales[strings.ToLower(primary)] && strings.ToUpper(rest) != rest {
+			return UNKNOWN_LANGUAGE
+		}
+	case len(rest) == 3 || len(rest) == 4:
+		// A script, such as "hant", or a UN M.49 region, such as "419"
+		if !urlLocales[strings.ToLower(primary)] {
+			return UNKNOWN_LANGUAGE
+		}
+	default:
+		return UNKNOWN_LANGUAGE
+	}
+	return LanguageFromTag(s)
+}
+
+// raisePrior raises the prior of lang in h to weight, if it is lower,
+// adding Priors to h if it has none.
+func (h *Hints) raisePrior(lang Language, weight int) {
+	if h.Priors == nil {
+		h.Priors = new(Priors)
+	}
+	if old := h.Priors.weights[lang]; old < weight {
+		h.Priors.Add(lang, weight-old)
+	}
+}
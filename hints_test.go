@@ -0,0 +1,122 @@
+package cld2
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestLanguageFromTag(t *testing.T) {
+	for tag, want := range map[string]Language{
+		"de":         GERMAN,
+		"pt-BR":      PORTUGUESE,
+		"EN-us":      ENGLISH,
+		"he":         HEBREW,
+		"zh-TW":      CHINESE_T,
+		"zh-Hant-HK": CHINESE_T,
+		"sr-Latn-ME": MONTENEGRIN,
+		"en_GB":      ENGLISH,
+		"x-klingon":  UNKNOWN_LANGUAGE,
+		"":           UNKNOWN_LANGUAGE,
+	} {
+		if got := LanguageFromTag(tag); got != want {
+			t.Errorf("%q: want %v, got %v", tag, want, got)
+		}
+	}
+}
+
+func TestHintsFromAcceptLanguage(t *testing.T) {
+	for _, tc := range []struct {
+		header string
+		want   []Prior
+	}{
+		{"", nil},
+		{"da", []Prior{{DANISH, 6}}},
+		{"fr-CH, fr;q=0.9, en;q=0.8, de;q=0.7, *;q=0.5",
+			[]Prior{{FRENCH, 6}, {ENGLISH, 5}, {GERMAN, 4}}},
+		// Region subtags map to their language; the highest weight wins.
+		{"en-GB;q=0.5, en-US", []Prior{{ENGLISH, 6}}},
+		{"pt-BR;q=0.2,es;Q=0.05", []Prior{{PORTUGUESE, 1}, {SPANISH, 1}}},
+		// Wildcards and q=0 say nothing about the text.
+		{"*", nil},
+		{"nl;q=0", nil},
+		// Malformed entries are skipped, the rest kept.
+		{"de;q=high, fr;q=1.5, ;q=0.5, en-;q=0.4, 12, ja;x=1, it;q=0.5", []Prior{{ITALIAN, 3}}},
+		{"qaa, x-private", nil},
+	} {
+		hints := HintsFromAcceptLanguage(tc.header)
+		if hints.Encoding != UNKNOWN_ENCODING || hints.Language != UNKNOWN_LANGUAGE {
+			t.Errorf("%q: want no other hints, got %+v", tc.header, hints)
+		}
+		var got []Prior
+		if hints.Priors != nil {
+			got = hints.Priors.List()
+		}
+		if len(got) != len(tc.want) {
+			t.Errorf("%q: want %v, got %v", tc.header, tc.want, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != tc.want[i] {
+				t.Errorf("%q: want %v, got %v", tc.header, tc.want, got)
+				break
+			}
+		}
+	}
+}
+
+func TestHintsFromURL(t *testing.T) {
+	for _, tc := range []struct {
+		url  string
+		tld  string
+		want []Prior
+	}{
+		{"https://www.example.de/impressum", "de", nil},
+		{"https://de.example.com/", "com", []Prior{{GERMAN, 4}}},
+		{"https://example.co.uk/", "uk", nil},
+		{"https://fr.example.co.uk/", "uk", []Prior{{FRENCH, 4}}},
+		{"https://loja.example.com.br/pt-br/produtos", "br", []Prior{{PORTUGUESE, 6}}},
+		{"http://example.com:8080/en_US/about?lang=ja", "com", []Prior{{JAPANESE, 8}, {ENGLISH, 6}}},
+		{"https://example.org/es", "org", []Prior{{SPANISH, 6}}},
+		{"https://www.google.com/search?q=x&hl=nl", "com", []Prior{{DUTCH, 8}}},
+		// The same language keeps its highest weight.
+		{"https://it.example.com/it/?locale=it_IT", "com", []Prior{{ITALIAN, 8}}},
+		// Words that aren't locales, and hosts without a TLD.
+		{"https://api.example.com/video/de-facto", "com", nil},
+		{"https://example.com/new/items", "com", nil},
+		{"https://example.com/war/", "com", nil},
+		{"https://example.com/id/123", "com", nil},
+		{"https://my.example.com/my/account", "com", nil},
+		{"https://eu.example.com/to-do/", "com", nil},
+		{"https://ca.example.com/it/", "com", nil},
+		{"https://example.com/it_IT/", "com", []Prior{{ITALIAN, 6}}},
+		{"https://example.com/zh-hant/", "com", []Prior{{CHINESE_T, 6}}},
+		{"http://192.168.1.1/de/", "", []Prior{{GERMAN, 6}}},
+		{"http://localhost/", "", nil},
+	} {
+		u, err := url.Parse(tc.url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		hints := HintsFromURL(u)
+		if hints.TLD != tc.tld {
+			t.Errorf("%s: want TLD %q, got %q", tc.url, tc.tld, hints.TLD)
+		}
+		var got []Prior
+		if hints.Priors != nil {
+			got = hints.Priors.List()
+		}
+		if len(got) != len(tc.want) {
+			t.Errorf("%s: want %v, got %v", tc.url, tc.want, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != tc.want[i] {
+				t.Errorf("%s: want %v, got %v", tc.url, tc.want, got)
+				break
+			}
+		}
+	}
+	if hints := HintsFromURL(nil); hints != NoHints {
+		t.Errorf("want no hints for nil URL, got %+v", hints)
+	}
+}
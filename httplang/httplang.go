@@ -0,0 +1,125 @@
+// Package httplang provides net/http middleware that detects the
+// language of request bodies with CLD2.
+//
+// The middleware reads the start of each text body, detects its
+// language with hints from the request, and stores the result in the
+// request context, where handlers find it with FromContext. The body is
+// restored, so handlers read it in full as if nothing had happened.
+package httplang
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"cld2"
+)
+
+// DefaultMaxBytes is how much of a body is read when Options.MaxBytes
+// is 0. CLD2 is about as sure after 64 KB of text as after more.
+const DefaultMaxBytes = 64 << 10
+
+// Options control Handler.
+type Options struct {
+	MaxBytes int64 // body bytes read for detection, or DefaultMaxBytes if 0
+
+	// Detect are the options for detection, such as a Calibration or a
+	// ReliabilityPolicy. HTML and Hints are set from each request.
+	Detect cld2.Options
+}
+
+type contextKey struct{}
+
+// Handler returns a handler that detects the language of the body of
+// each request and then calls next.
+//
+// Bodies are detected if their Content-Type is text, HTML, XML or JSON,
+// or missing; HTML is detected as such, and a charset other than UTF-8
+// is converted first. The Content-Language header, the top-level domain
+// of the Host header and languages in the host and URL are used as
+// hints, as by HintsFromURL. Requests without a body, or whose body
+// can't be read or converted, are passed on without a result.
+func Handler(next http.Handler, opts Options) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if res, ok := detect(r, opts); ok {
+			r = r.WithContext(context.WithValue(r.Context(), contextKey{}, res))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// FromContext returns the language detected for the request body by
+// Handler, and whether there is one.
+func FromContext(ctx context.Context) (cld2.Languages, bool) {
+	res, ok := ctx.Value(contextKey{}).(cld2.Languages)
+	return res, ok
+}
+
+// body is a request body with the bytes already read put back.
+type body struct {
+	io.Reader
+	io.Closer
+}
+
+// detect reads the start of the body of r, puts it back, and detects
+// its language.
+func detect(r *http.Request, opts Options) (cld2.Languages, bool) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return cld2.Languages{}, false
+	}
+	mediaType, params := "", map[string]string(nil)
+	if ct := r.Header.Get("Content-Type"); ct != "" {
+		var err error
+		if mediaType, params, err = mime.ParseMediaType(ct); err != nil {
+			return cld2.Languages{}, false
+		}
+	}
+	if !isText(mediaType) {
+		return cld2.Languages{}, false
+	}
+
+	max := opts.MaxBytes
+	if max <= 0 {
+		max = DefaultMaxBytes
+	}
+	buf, err := ioutil.ReadAll(io.LimitReader(r.Body, max))
+	r.Body = body{io.MultiReader(bytes.NewReader(buf), r.Body), r.Body}
+	if err != nil || len(buf) == 0 {
+		return cld2.Languages{}, false
+	}
+
+	hints := cld2.HintsFromURL(&url.URL{Host: r.Host, Path: r.URL.Path, RawQuery: r.URL.RawQuery})
+	hints.ContentLanguage = r.Header.Get("Content-Language")
+	o := opts.Detect
+	o.HTML = mediaType == "text/html" || mediaType == "application/xhtml+xml"
+	o.Hints = &hints
+
+	// A character cut off at max is left out
+	enc := cld2.ParseEncoding(params["charset"])
+	if enc == cld2.UNKNOWN_ENCODING {
+		enc = cld2.UTF8
+	}
+	res, err := cld2.DetectEncodedWithOptions(buf, enc, o)
+	if err != nil {
+		return cld2.Languages{}, false
+	}
+	return res, true
+}
+
+// isText reports whether bodies of mediaType are worth detecting.
+func isText(mediaType string) bool {
+	switch {
+	case mediaType == "", strings.HasPrefix(mediaType, "text/"):
+		return true
+	case mediaType == "application/xhtml+xml", mediaType == "application/xml",
+		mediaType == "application/json", strings.HasSuffix(mediaType, "+json"),
+		strings.HasSuffix(mediaType, "+xml"):
+		return true
+	}
+	return false
+}
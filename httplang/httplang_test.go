@@ -0,0 +1,105 @@
+package httplang
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"cld2"
+)
+
+const deText = `Freuen Sie sich auf eine Berlin-Story zur Wiedervereinigung und eine bewegende Ost-West-Liebesgeschichte. ` +
+	`Die Geschichte erzählt von zwei Menschen, die sich nach dem Fall der Mauer zum ersten Mal begegnen.`
+
+func TestHandler(t *testing.T) {
+	var got cld2.Languages
+	var found bool
+	var body string
+	h := Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, found = FromContext(r.Context())
+		b, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body = string(b)
+	}), Options{MaxBytes: 64})
+
+	req := httptest.NewRequest("POST", "http://example.de/kommentare", strings.NewReader(deText))
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	if !found || len(got.Estimates) == 0 || got.Estimates[0].Language != cld2.GERMAN {
+		t.Errorf("want German in the context, got %v %+v", found, got)
+	}
+	if body != deText {
+		t.Errorf("want the whole body downstream, got %q", body)
+	}
+
+	req = httptest.NewRequest("POST", "http://example.de/", strings.NewReader("\x89PNG"))
+	req.Header.Set("Content-Type", "image/png")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	if found || body != "\x89PNG" {
+		t.Errorf("want images passed on untouched, got %v %q", found, body)
+	}
+
+	req = httptest.NewRequest("GET", "http://example.de/", nil)
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	if found {
+		t.Errorf("want no result without a body, got %+v", got)
+	}
+}
+
+func TestHandlerCharset(t *testing.T) {
+	var got cld2.Languages
+	var found bool
+	h := Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, found = FromContext(r.Context())
+	}), Options{MaxBytes: 64})
+
+	// "日本語のテキスト" in Shift_JIS, after one byte so that the body is
+	// cut in the middle of a character
+	body := "a" + strings.Repeat("\x93\xfa\x96\x7b\x8c\xea\x82\xcc\x83\x65\x83\x4c\x83\x58\x83\x67", 8)
+	req := httptest.NewRequest("POST", "http://example.com/", strings.NewReader(body))
+	req.Header.Set("Content-Type", "text/plain; charset=Shift_JIS")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	if !found || len(got.Estimates) == 0 || got.Estimates[0].Language != cld2.JAPANESE {
+		t.Errorf("want Japanese in the context, got %v %+v", found, got)
+	}
+}
+
+func TestHandlerContentLanguage(t *testing.T) {
+	var got cld2.Languages
+	h := Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = FromContext(r.Context())
+	}), Options{})
+
+	// Too short to tell Danish from Norwegian without the header
+	for tag, want := range map[string]cld2.Language{"da": cld2.DANISH, "nb": cld2.NORWEGIAN} {
+		req := httptest.NewRequest("POST", "http://example.com/", strings.NewReader("hej med dig"))
+		req.Header.Set("Content-Type", "text/plain")
+		req.Header.Set("Content-Language", tag)
+		h.ServeHTTP(httptest.NewRecorder(), req)
+		if len(got.Estimates) == 0 || got.Estimates[0].Language != want {
+			t.Errorf("Content-Language %s: want %v, got %+v", tag, want, got.Estimates)
+		}
+	}
+}
+
+func TestHandlerCutRune(t *testing.T) {
+	// deText cut in the middle of the "ä" of "erzählt"
+	max := strings.Index(deText, "ä") + 1
+	var got cld2.Languages
+	h := Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = FromContext(r.Context())
+	}), Options{MaxBytes: int64(max)})
+
+	for _, ct := range []string{"text/plain; charset=utf-8", "text/plain"} {
+		req := httptest.NewRequest("POST", "http://example.com/", strings.NewReader(deText))
+		req.Header.Set("Content-Type", ct)
+		h.ServeHTTP(httptest.NewRecorder(), req)
+		if got.UTF8.Damaged() {
+			t.Errorf("%s: want the cut character left out, got %+v", ct, got.UTF8)
+		}
+	}
+}
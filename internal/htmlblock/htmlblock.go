@@ -0,0 +1,331 @@
+// Package htmlblock splits HTML documents into the elements that hold
+// their text, with the language each declares or inherits.
+package htmlblock
+
+import (
+	"bytes"
+	"strings"
+)
+
+// Block is an element of an HTML document that holds text of its own:
+// a block-level element such as <p> or <li>, or any element with a lang
+// attribute. Text inside a nested block belongs to that block instead.
+type Block struct {
+	Tag    string // element name, lowercased
+	Offset int    // byte offset of the start tag
+	Length int    // bytes through the end tag, or up to where the element was closed implicitly
+
+	// Lang is the lang or xml:lang of the element, or the one it
+	// inherits, or "" if there is none. Declared reports whether the
+	// element has its own.
+	Lang      string
+	Declared  bool
+	LangAttrs []Range // the values of its lang and xml:lang attributes
+
+	Text []Range // the text of the element, outside tags and nested blocks
+}
+
+// Range is a run of bytes of a document.
+type Range struct {
+	Offset int
+	Length int
+}
+
+// blockTags are the elements that always make a Block. html, body and
+// title are included so that all text of a page belongs to some block.
+var blockTags = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true,
+	"body": true, "caption": true, "dd": true, "details": true,
+	"dialog": true, "div": true, "dl": true, "dt": true,
+	"fieldset": true, "figcaption": true, "figure": true, "footer": true,
+	"form": true, "h1": true, "h2": true, "h3": true, "h4": true,
+	"h5": true, "h6": true, "header": true, "html": true, "legend": true,
+	"li": true, "main": true, "nav": true, "ol": true, "option": true,
+	"p": true, "pre": true, "section": true, "summary": true,
+	"table": true, "td": true, "th": true, "title": true, "tr": true,
+	"ul": true,
+}
+
+// voidTags are the elements that have no end tag.
+var voidTags = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true,
+	"hr": true, "img": true, "input": true, "link": true, "meta": true,
+	"param": true, "source": true, "track": true, "wbr": true,
+}
+
+// rawTags are the elements whose content is not text or markup.
+var rawTags = map[string]bool{"script": true, "style": true}
+
+// autoCloses reports whether a start tag closes an open element
+// implicitly, as the HTML parser does for paragraphs, list items and
+// table cells.
+func autoCloses(tag, open string) bool {
+	switch open {
+	case "p":
+		return blockTags[tag]
+	case "li":
+		return tag == "li"
+	case "dt", "dd":
+		return tag == "dt" || tag == "dd"
+	case "td", "th":
+		return tag == "td" || tag == "th" || tag == "tr"
+	case "tr":
+		return tag == "tr"
+	case "option":
+		return tag == "option"
+	}
+	return false
+}
+
+// Scan splits an HTML document into the blocks that hold its
+// text, in document order, with the language each declares or inherits.
+// It is a forgiving scanner rather than a full HTML parser: comments,
+// scripts and styles are skipped, end tags close the nearest open
+// element of their name, and paragraphs, list items and table cells are
+// closed implicitly. Text outside any element is left out.
+func Scan(html []byte) []Block {
+	type element struct {
+		tag   string
+		lang  string
+		block int // index in blocks, or -1
+	}
+	var blocks []Block
+	var stack []element
+	// closeTo closes stack[j] at end and the elements inside it, which
+	// have no end tag of their own, at start.
+	closeTo := func(j, start, end int) {
+		for k := len(stack) - 1; k >= j; k-- {
+			if b := stack[k].block; b >= 0 {
+				blocks[b].Length = start - blocks[b].Offset
+				if k == j {
+					blocks[b].Length = end - blocks[b].Offset
+				}
+			}
+		}
+		stack = stack[:j]
+	}
+	current := func() int {
+		for k := len(stack) - 1; k >= 0; k-- {
+			if stack[k].block >= 0 {
+				return stack[k].block
+			}
+		}
+		return -1
+	}
+
+	for i := 0; i < len(html); {
+		if html[i] != '<' {
+			end := bytes.IndexByte(html[i:], '<')
+			if end < 0 {
+				end = len(html)
+			} else {
+				end += i
+			}
+			if b := current(); b >= 0 && len(bytes.TrimSpace(html[i:end])) > 0 {
+				blocks[b].Text = append(blocks[b].Text, Range{Offset: i, Length: end - i})
+			}
+			i = end
+			continue
+		}
+
+		t, ok := scanTag(html, i)
+		if !ok {
+			// A lone '<' is text
+			if b := current(); b >= 0 {
+				blocks[b].Text = append(blocks[b].Text, Range{Offset: i, Length: 1})
+			}
+			i++
+			continue
+		}
+		switch {
+		case t.name == "":
+			// Comment, doctype or processing instruction
+		case t.end:
+			for j := len(stack) - 1; j >= 0; j-- {
+				if stack[j].tag == t.name {
+					closeTo(j, i, t.next)
+					break
+				}
+			}
+		default:
+			for {
+				j := len(stack) - 1
+				for j >= 0 && !blockTags[stack[j].tag] {
+					j--
+				}
+				if j < 0 || !autoCloses(t.name, stack[j].tag) {
+					break
+				}
+				closeTo(j, i, i)
+			}
+			if voidTags[t.name] || t.selfClosing {
+				break
+			}
+			e := element{tag: t.name, block: -1}
+			if len(stack) > 0 {
+				e.lang = stack[len(stack)-1].lang
+			}
+			if t.hasLang {
+				e.lang = t.lang
+			}
+			if blockTags[t.name] || t.hasLang {
+				e.block = len(blocks)
+				blocks = append(blocks, Block{Tag: t.name, Offset: i, Length: len(html) - i, Lang: e.lang, Declared: t.hasLang, LangAttrs: t.langAttrs})
+			}
+			stack = append(stack, e)
+			if rawTags[t.name] {
+				// Skip to the end tag
+				end := indexFold(html[t.next:], "</"+t.name)
+				if end < 0 {
+					t.next = len(html)
+				} else {
+					t.next += end
+				}
+			}
+		}
+		i = t.next
+	}
+	closeTo(0, len(html), len(html))
+	return blocks
+}
+
+// htmlTag is a tag read by scanTag.
+type htmlTag struct {
+	name        string // lowercased; "" for comments and declarations
+	end         bool   // an end tag
+	selfClosing bool   // ends in "/>"
+	lang        string // the lang attribute, or else xml:lang
+	hasLang     bool
+	langAttrs   []Range // where the values of lang and xml:lang are
+	next        int     // offset just past the tag
+}
+
+// scanTag reads the tag starting at html[i], which is '<'. It returns
+// false if the '<' doesn't start a tag.
+func scanTag(html []byte, i int) (htmlTag, bool) {
+	var t htmlTag
+	rest := html[i:]
+	switch {
+	case bytes.HasPrefix(rest, []byte("<!--")):
+		end := bytes.Index(rest[4:], []byte("-->"))
+		t.next = len(html)
+		if end >= 0 {
+			t.next = i + 4 + end + 3
+		}
+		return t, true
+	case bytes.HasPrefix(rest, []byte("<!")), bytes.HasPrefix(rest, []byte("<?")):
+		t.next = skipTag(html, i+2)
+		return t, true
+	}
+	j := i + 1
+	if j < len(html) && html[j] == '/' {
+		t.end = true
+		j++
+	}
+	if j >= len(html) || !IsASCIILetter(html[j]) {
+		return t, false
+	}
+	start := j
+	for j < len(html) && !IsSpace(html[j]) && html[j] != '>' && html[j] != '/' {
+		j++
+	}
+	t.name = strings.ToLower(string(html[start:j]))
+
+	var xmlLang string
+	var hasXMLLang bool
+	for j < len(html) {
+		for j < len(html) && (IsSpace(html[j]) || html[j] == '/') {
+			if html[j] == '/' && j+1 < len(html) && html[j+1] == '>' {
+				t.selfClosing = true
+			}
+			j++
+		}
+		if j >= len(html) || html[j] == '>' {
+			break
+		}
+		start := j
+		for j < len(html) && !IsSpace(html[j]) && html[j] != '=' && html[j] != '>' && html[j] != '/' {
+			j++
+		}
+		name := strings.ToLower(string(html[start:j]))
+		for j < len(html) && IsSpace(html[j]) {
+			j++
+		}
+		var value string
+		var valueAt Range
+		hasValue := false
+		if j < len(html) && html[j] == '=' {
+			hasValue = true
+			j++
+			for j < len(html) && IsSpace(html[j]) {
+				j++
+			}
+			if j < len(html) && (html[j] == '"' || html[j] == '\'') {
+				q := html[j]
+				end := bytes.IndexByte(html[j+1:], q)
+				if end < 0 {
+					end = len(html) - j - 1
+				}
+				value = string(html[j+1 : j+1+end])
+				valueAt = Range{Offset: j + 1, Length: end}
+				j += end + 2
+			} else {
+				start := j
+				for j < len(html) && !IsSpace(html[j]) && html[j] != '>' {
+					j++
+				}
+				value = string(html[start:j])
+				valueAt = Range{Offset: start, Length: j - start}
+			}
+		}
+		if hasValue && (name == "lang" || name == "xml:lang") {
+			t.langAttrs = append(t.langAttrs, valueAt)
+		}
+		switch name {
+		case "lang":
+			t.lang, t.hasLang = strings.TrimSpace(value), true
+		case "xml:lang":
+			xmlLang, hasXMLLang = strings.TrimSpace(value), true
+		}
+	}
+	if !t.hasLang && hasXMLLang {
+		t.lang, t.hasLang = xmlLang, true
+	}
+	if t.end {
+		t.hasLang, t.langAttrs = false, nil
+	}
+	t.next = j + 1
+	if t.next > len(html) {
+		t.next = len(html)
+	}
+	return t, true
+}
+
+// skipTag returns the offset just past the next '>' at or after j.
+func skipTag(html []byte, j int) int {
+	end := bytes.IndexByte(html[j:], '>')
+	if end < 0 {
+		return len(html)
+	}
+	return j + end + 1
+}
+
+// indexFold is bytes.Index ignoring case.
+func indexFold(s []byte, sep string) int {
+	for i := 0; i+len(sep) <= len(s); i++ {
+		if bytes.EqualFold(s[i:i+len(sep)], []byte(sep)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// IsASCIILetter reports whether c is an ASCII letter.
+func IsASCIILetter(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+// IsSpace reports whether c is space in HTML.
+func IsSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
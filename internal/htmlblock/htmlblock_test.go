@@ -0,0 +1,82 @@
+package htmlblock
+
+import "testing"
+
+func TestScan(t *testing.T) {
+	html := `<!DOCTYPE html>
+<html lang="en"><head><title>Help</title><script>var s = "<p>no</p>";</script></head>
+<body>
+<!-- <p lang="de">comment</p> -->
+<p>First <b>bold</b> paragraph
+<p xml:lang="fr">Deuxième <span lang="de">deutsch</span> fin</p>
+<ul><li>one<li LANG=nl>twee</ul>
+<div lang="">unknown<br>text</div>
+</body></html>`
+	type want struct {
+		tag, lang string
+		declared  bool
+		text      []string
+		closing   string // the end of the block
+	}
+	wants := []want{
+		{"html", "en", true, nil, "</html>"},
+		{"title", "en", false, []string{"Help"}, "</title>"},
+		{"body", "en", false, nil, "</body>"},
+		{"p", "en", false, []string{"First ", "bold", " paragraph\n"}, "paragraph\n"},
+		{"p", "fr", true, []string{"Deuxième ", " fin"}, "</p>"},
+		{"span", "de", true, []string{"deutsch"}, "</span>"},
+		{"ul", "en", false, nil, "</ul>"},
+		{"li", "en", false, []string{"one"}, "one"},
+		{"li", "nl", true, []string{"twee"}, "twee"},
+		{"div", "", true, []string{"unknown", "text"}, "</div>"},
+	}
+	got := Scan([]byte(html))
+	if len(got) != len(wants) {
+		t.Fatalf("want %d blocks, got %d: %+v", len(wants), len(got), got)
+	}
+	for i, w := range wants {
+		b := got[i]
+		if b.Tag != w.tag || b.Lang != w.lang || b.Declared != w.declared {
+			t.Errorf("block %d: want <%s> lang %q declared %v, got <%s> lang %q declared %v", i, w.tag, w.lang, w.declared, b.Tag, b.Lang, b.Declared)
+		}
+		var text []string
+		for _, r := range b.Text {
+			text = append(text, html[r.Offset:r.Offset+r.Length])
+		}
+		if len(text) != len(w.text) {
+			t.Errorf("block %d <%s>: want text %q, got %q", i, w.tag, w.text, text)
+		} else {
+			for j := range text {
+				if text[j] != w.text[j] {
+					t.Errorf("block %d <%s>: want text %q, got %q", i, w.tag, w.text, text)
+					break
+				}
+			}
+		}
+		if html[b.Offset] != '<' || html[b.Offset+1:b.Offset+1+len(b.Tag)] != b.Tag {
+			t.Errorf("block %d: want offset at start tag, got %q", i, html[b.Offset:])
+		}
+		if end := html[:b.Offset+b.Length]; len(end) < len(w.closing) || end[len(end)-len(w.closing):] != w.closing {
+			t.Errorf("block %d <%s>: want it to end in %q, got %q", i, w.tag, w.closing, html[b.Offset:b.Offset+b.Length])
+		}
+	}
+
+	if got := Scan([]byte("no tags < here")); len(got) != 0 {
+		t.Errorf("want no blocks without elements, got %+v", got)
+	}
+	for _, b := range got {
+		if len(b.LangAttrs) == 0 && b.Declared {
+			t.Errorf("<%s>: want where its lang is", b.Tag)
+		}
+		for _, r := range b.LangAttrs {
+			if v := html[r.Offset : r.Offset+r.Length]; v != b.Lang {
+				t.Errorf("<%s>: want lang value %q, got %q", b.Tag, b.Lang, v)
+			}
+		}
+	}
+
+	got = Scan([]byte(`<p lang="de">offen`))
+	if len(got) != 1 || got[0].Length != len(`<p lang="de">offen`) {
+		t.Errorf("want an unclosed block to run to the end, got %+v", got)
+	}
+}
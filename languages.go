@@ -7,10 +7,21 @@ type Estimate struct {
 	Language Language
 	Percent  int // text percentage 0..100 of the top 3 languages.
 
-	// NormScore is internal language scores as a ratio to normal score for real text in that language.
-	// Scores close to 1.0 indicate normal text, while scores far away
-	// from 1.0 indicate badly-skewed text or gibberish.
+	// NormScore is the internal language score per 1024 bytes of text.
+	// Scores close to the average for real text in that language indicate
+	// normal text, while scores far away from it indicate badly-skewed
+	// text or gibberish; Quality makes that comparison.
 	NormScore float64
+
+	// Confidence is the probability, 0..1, that the text or Percent of
+	// it is in this language, from Options.Calibration. Unlike Percent
+	// and Reliable it weighs reliability, score, margin, length and script
+	// together. It is 0 without a Calibration, and from DetectThree.
+	Confidence float64
+
+	// Group is the label of the close set of Language, such as
+	// "bs+hr+sr+sr-ME", if Options.CloseSets is set.
+	Group string
 }
 
 // Language is a single language.
@@ -19,13 +30,34 @@ type Language uint16
 
 // Languages are probable languages of the supplied text
 type Languages struct {
-	Estimates []Estimate // Possible languages returned in order of confidence
-	TextBytes int        // the amount of non-tag/letters-only text found
-	Reliable  bool       // Does CLD2 see the result as reliable?
+	Estimates  []Estimate           // Possible languages returned in order of confidence
+	TextBytes  int                  // the amount of non-tag/letters-only text found
+	Reliable   bool                 // Does CLD2, or Options.Reliability, see the result as reliable?
+	Unreliable ReliabilityCriterion // the criterion that made it unreliable, if any
+	Ambiguity  []Alternative        // close languages CLD2 merged into the estimates
+	Priors     []AppliedPrior       // priors CLD2 applied from hints and HTML lang attributes
+	UTF8       UTF8Report           // ill-formed UTF-8 repaired before detection
+	Spans      []Span               // language of each span of the text, if asked for
+
+	// WithoutHints is the result of detecting the text without hints or
+	// HTML lang attributes, but otherwise with the same options, if
+	// Options.WithoutHints is set.
+	WithoutHints *Languages
 }
 
-func (l Language) String() string {
-	return strings.Replace(strings.Title(strings.ToLower(languageToCName[int(l)])), "_", " ", -1)
+// FlippedByHints reports whether hints changed the top language, as
+// seen by comparing with WithoutHints. It is false if WithoutHints is nil.
+func (l Languages) FlippedByHints() bool {
+	if l.WithoutHints == nil {
+		return false
+	}
+	top := func(l Languages) Language {
+		if len(l.Estimates) == 0 {
+			return UNKNOWN_LANGUAGE
+		}
+		return l.Estimates[0].Language
+	}
+	return top(l) != top(*l.WithoutHints)
 }
 
 func (l Language) Code() string {
@@ -51,6 +83,27 @@ func LanguageFromCode(code string) Language {
 	return UNKNOWN_LANGUAGE
 }
 
+// LanguageFromTag returns the language of a language tag such as
+// "pt-BR", "zh-Hant-TW" or "he", ignoring case. Like GetLanguageFromName
+// in lang_script.cc it tries the whole tag, then "aa-bb" and "aa-cc"
+// of "aa-bb-cc", then the primary subtag "aa". Returns
+// UNKNOWN_LANGUAGE if none of them is known.
+func LanguageFromTag(tag string) Language {
+	tag = strings.ToLower(strings.Replace(strings.TrimSpace(tag), "_", "-", -1))
+	sub := strings.Split(tag, "-")
+	tries := []string{tag}
+	if len(sub) > 2 {
+		tries = append(tries, sub[0]+"-"+sub[1], sub[0]+"-"+sub[2])
+	}
+	tries = append(tries, sub[0])
+	for _, t := range tries {
+		if l, ok := tagToLanguage[t]; ok {
+			return l
+		}
+	}
+	return UNKNOWN_LANGUAGE
+}
+
 // Copied from "generated_language.h"
 const (
 	ENGLISH                  Language = 0   // en
@@ -672,12 +725,18 @@ const (
 
 var codeToLanguage = make(map[string]Language)
 
+// tagToLanguage maps the lowercased codes of codeLookup for LanguageFromTag.
+var tagToLanguage = make(map[string]Language)
+
 func init() {
 	for i, code := range languageToCode {
 		if code != "" {
 			codeToLanguage[code] = Language(i)
 		}
 	}
+	for code, lang := range codeLookup {
+		tagToLanguage[strings.ToLower(code)] = lang
+	}
 }
 
 var languageToCode = []string{
@@ -0,0 +1,166 @@
+package cld2
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// The numeric values of Language follow the CLD2 tables and are not
+// guaranteed to be stable between table versions, so everything that
+// leaves the process is written as the language code instead.
+
+// MarshalText implements encoding.TextMarshaler.
+// The language is encoded as its language code.
+func (l Language) MarshalText() ([]byte, error) {
+	if l >= NUM_LANGUAGES || l.Code() == "" {
+		return nil, fmt.Errorf("cld2: cannot marshal invalid language %d", uint16(l))
+	}
+	return []byte(l.Code()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+// It accepts the codes returned by Language.Code.
+func (l *Language) UnmarshalText(text []byte) error {
+	lang, ok := codeToLanguage[string(text)]
+	if !ok {
+		return fmt.Errorf("cld2: unknown language code %q", text)
+	}
+	*l = lang
+	return nil
+}
+
+// Scan implements sql.Scanner.
+// The column must hold a language code; NULL scans as UNKNOWN_LANGUAGE.
+func (l *Language) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*l = UNKNOWN_LANGUAGE
+		return nil
+	case string:
+		return l.UnmarshalText([]byte(v))
+	case []byte:
+		return l.UnmarshalText(v)
+	}
+	return fmt.Errorf("cld2: cannot scan %T into Language", src)
+}
+
+// Value implements driver.Valuer.
+// The language is stored as its language code.
+func (l Language) Value() (driver.Value, error) {
+	text, err := l.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return string(text), nil
+}
+
+// estimateJSON is the stable JSON form of an Estimate.
+type estimateJSON struct {
+	Code       Language `json:"code"`
+	Name       string   `json:"name"`
+	Percent    int      `json:"percent"`
+	NormScore  float64  `json:"normalized_score"`
+	Confidence float64  `json:"confidence,omitempty"`
+	Group      string   `json:"group,omitempty"`
+}
+
+// spanJSON is the stable JSON form of a Span. The rune and UTF-16
+// offsets are left out unless they were asked for.
+type spanJSON struct {
+	Code        Language `json:"code"`
+	Offset      int      `json:"offset"`
+	Length      int      `json:"length"`
+	RuneOffset  *int     `json:"rune_offset,omitempty"`
+	RuneLength  *int     `json:"rune_length,omitempty"`
+	UTF16Offset *int     `json:"utf16_offset,omitempty"`
+	UTF16Length *int     `json:"utf16_length,omitempty"`
+}
+
+// languagesJSON is the stable JSON form of Languages.
+type languagesJSON struct {
+	Estimates  []Estimate           `json:"estimates"`
+	TextBytes  int                  `json:"text_bytes"`
+	Reliable   bool                 `json:"reliable"`
+	Unreliable ReliabilityCriterion `json:"unreliable,omitempty"`
+	Ambiguity  []Alternative        `json:"ambiguity,omitempty"`
+	Priors     []AppliedPrior       `json:"priors,omitempty"`
+	UTF8       *UTF8Report          `json:"utf8,omitempty"`
+	Spans      []Span               `json:"spans,omitempty"`
+
+	WithoutHints *Languages `json:"without_hints,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler.
+// The language is written both as its code and its name;
+// only the code is used when decoding.
+func (e Estimate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(estimateJSON{
+		Code:       e.Language,
+		Name:       e.Language.String(),
+		Percent:    e.Percent,
+		NormScore:  e.NormScore,
+		Confidence: e.Confidence,
+		Group:      e.Group,
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (e *Estimate) UnmarshalJSON(data []byte) error {
+	var v estimateJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*e = Estimate{Language: v.Code, Percent: v.Percent, NormScore: v.NormScore, Confidence: v.Confidence, Group: v.Group}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s Span) MarshalJSON() ([]byte, error) {
+	v := spanJSON{Code: s.Language, Offset: s.Offset, Length: s.Length}
+	// A span is never empty, so it has runes if they were counted.
+	if s.RuneLength > 0 {
+		v.RuneOffset, v.RuneLength = &s.RuneOffset, &s.RuneLength
+		v.UTF16Offset, v.UTF16Length = &s.UTF16Offset, &s.UTF16Length
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *Span) UnmarshalJSON(data []byte) error {
+	var v spanJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*s = Span{Offset: v.Offset, Length: v.Length, Language: v.Code}
+	if v.RuneOffset != nil && v.RuneLength != nil && v.UTF16Offset != nil && v.UTF16Length != nil {
+		s.RuneOffset, s.RuneLength = *v.RuneOffset, *v.RuneLength
+		s.UTF16Offset, s.UTF16Length = *v.UTF16Offset, *v.UTF16Length
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (l Languages) MarshalJSON() ([]byte, error) {
+	v := languagesJSON{Estimates: l.Estimates, TextBytes: l.TextBytes, Reliable: l.Reliable, Unreliable: l.Unreliable, Ambiguity: l.Ambiguity, Priors: l.Priors, Spans: l.Spans, WithoutHints: l.WithoutHints}
+	if v.Estimates == nil {
+		v.Estimates = []Estimate{}
+	}
+	if l.UTF8.Damaged() {
+		v.UTF8 = &l.UTF8
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (l *Languages) UnmarshalJSON(data []byte) error {
+	var v languagesJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*l = Languages{Estimates: v.Estimates, TextBytes: v.TextBytes, Reliable: v.Reliable, Unreliable: v.Unreliable, Ambiguity: v.Ambiguity, Priors: v.Priors, Spans: v.Spans, WithoutHints: v.WithoutHints}
+	if v.UTF8 != nil {
+		l.UTF8 = *v.UTF8
+	}
+	return nil
+}
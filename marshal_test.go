@@ -0,0 +1,170 @@
+package cld2
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestLanguageText(t *testing.T) {
+	b, err := json.Marshal(map[string]Language{"lang": DANISH})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"lang":"da"}` {
+		t.Errorf("want language to marshal as its code, got %s", b)
+	}
+
+	var v map[string]Language
+	if err := json.Unmarshal(b, &v); err != nil {
+		t.Fatal(err)
+	}
+	if v["lang"] != DANISH {
+		t.Errorf("want DANISH, got %v", v["lang"])
+	}
+
+	var l Language
+	if err := l.UnmarshalText([]byte("something")); err == nil {
+		t.Error("want error for unknown code")
+	}
+	if _, err := Language(81).MarshalText(); err == nil {
+		t.Error("want error for unused language number")
+	}
+	if _, err := NUM_LANGUAGES.MarshalText(); err == nil {
+		t.Error("want error for out of range language")
+	}
+}
+
+func TestLanguageSQL(t *testing.T) {
+	v, err := CHINESE_T.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != "zh-Hant" {
+		t.Errorf("want 'zh-Hant', got %v", v)
+	}
+
+	var l Language
+	for _, src := range []interface{}{"zh-Hant", []byte("zh-Hant")} {
+		l = ENGLISH
+		if err := l.Scan(src); err != nil {
+			t.Fatal(err)
+		}
+		if l != CHINESE_T {
+			t.Errorf("want CHINESE_T from %#v, got %v", src, l)
+		}
+	}
+	if err := l.Scan(nil); err != nil || l != UNKNOWN_LANGUAGE {
+		t.Errorf("want NULL to scan as UNKNOWN_LANGUAGE, got %v (%v)", l, err)
+	}
+	if err := l.Scan(42); err == nil {
+		t.Error("want error scanning an int")
+	}
+}
+
+func TestLanguagesJSON(t *testing.T) {
+	res := Languages{
+		Estimates: []Estimate{
+			{Language: GERMAN, Percent: 80, NormScore: 1.5},
+			{Language: ENGLISH, Percent: 19, NormScore: 0.25},
+		},
+		TextBytes: 1234,
+		Reliable:  true,
+	}
+	b, err := json.Marshal(res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"estimates":[` +
+		`{"code":"de","name":"German","percent":80,"normalized_score":1.5},` +
+		`{"code":"en","name":"English","percent":19,"normalized_score":0.25}],` +
+		`"text_bytes":1234,"reliable":true}`
+	if string(b) != want {
+		t.Errorf("want %s\n got %s", want, b)
+	}
+
+	var back Languages
+	if err := json.Unmarshal(b, &back); err != nil {
+		t.Fatal(err)
+	}
+	if len(back.Estimates) != 2 || back.Estimates[0] != res.Estimates[0] ||
+		back.Estimates[1] != res.Estimates[1] || back.TextBytes != res.TextBytes || !back.Reliable {
+		t.Errorf("want %+v after round trip, got %+v", res, back)
+	}
+
+	b, err = json.Marshal(Languages{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"estimates":[],"text_bytes":0,"reliable":false}` {
+		t.Errorf("want empty estimates list, got %s", b)
+	}
+
+	res.UTF8 = UTF8Report{InvalidSequences: 1, Bytes: 1}
+	b, err = json.Marshal(res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	back = Languages{}
+	if err := json.Unmarshal(b, &back); err != nil {
+		t.Fatal(err)
+	}
+	if back.UTF8 != res.UTF8 {
+		t.Errorf("want UTF-8 report %+v after round trip, got %+v", res.UTF8, back.UTF8)
+	}
+
+	res.Reliable, res.Unreliable = false, CriterionMargin
+	b, err = json.Marshal(res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	back = Languages{}
+	if err := json.Unmarshal(b, &back); err != nil {
+		t.Fatal(err)
+	}
+	if back.Reliable || back.Unreliable != CriterionMargin {
+		t.Errorf("want unreliable by %q after round trip, got %+v", CriterionMargin, back)
+	}
+
+	res.Priors = []AppliedPrior{{GERMAN, 10, []PriorSource{SourceHTMLLang, SourceTLD}}}
+	res.WithoutHints = &Languages{Estimates: []Estimate{{Language: DUTCH, Percent: 99}}}
+	b, err = json.Marshal(res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), `"priors":[{"code":"de","weight":10,"sources":["html-lang","tld"]}]`) {
+		t.Errorf("want applied priors in %s", b)
+	}
+	back = Languages{}
+	if err := json.Unmarshal(b, &back); err != nil {
+		t.Fatal(err)
+	}
+	if len(back.Priors) != 1 || back.Priors[0].Weight != 10 || len(back.Priors[0].Sources) != 2 {
+		t.Errorf("want applied priors after round trip, got %+v", back.Priors)
+	}
+	if !back.FlippedByHints() {
+		t.Errorf("want result without hints after round trip, got %+v", back.WithoutHints)
+	}
+}
+
+func TestSpanJSON(t *testing.T) {
+	b, err := json.Marshal([]Span{
+		{Offset: 3, Length: 8, Language: GERMAN},
+		{Offset: 11, Length: 8, Language: ENGLISH, RuneOffset: 3, RuneLength: 5, UTF16Offset: 4, UTF16Length: 6},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[{"code":"de","offset":3,"length":8},` +
+		`{"code":"en","offset":11,"length":8,"rune_offset":3,"rune_length":5,"utf16_offset":4,"utf16_length":6}]`
+	if string(b) != want {
+		t.Errorf("want %s\n got %s", want, b)
+	}
+	var back []Span
+	if err := json.Unmarshal(b, &back); err != nil {
+		t.Fatal(err)
+	}
+	if len(back) != 2 || back[1].UTF16Length != 6 || back[0].Language != GERMAN {
+		t.Errorf("want spans after round trip, got %+v", back)
+	}
+}
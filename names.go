@@ -0,0 +1,52 @@
+package cld2
+
+//go:generate go run gen_names.go
+
+import (
+	"sort"
+	"strconv"
+)
+
+// String returns the English name of the language,
+// e.g. "German" or "Chinese (Traditional)".
+func (l Language) String() string {
+	if int(l) < len(languageToName) && languageToName[l] != "" {
+		return languageToName[l]
+	}
+	return "Language(" + strconv.Itoa(int(l)) + ")"
+}
+
+// NativeName returns the name of the language in the language
+// itself, e.g. "Deutsch" or "日本語".
+// The English name is returned if no native name is known.
+func (l Language) NativeName() string {
+	if int(l) < len(languageToNativeName) && languageToNativeName[l] != "" {
+		return languageToNativeName[l]
+	}
+	return l.String()
+}
+
+// DisplayName returns the name of the language for a user interface
+// in language in, e.g. GERMAN.DisplayName(FRENCH) is "allemand".
+// The native name is returned when in is the language itself,
+// and the English name if there is no translation for in.
+func (l Language) DisplayName(in Language) string {
+	if in == l {
+		return l.NativeName()
+	}
+	if name, ok := displayNames[in][l]; ok {
+		return name
+	}
+	return l.String()
+}
+
+// DisplayLanguages returns the user interface languages
+// DisplayName has translations for, in addition to English.
+func DisplayLanguages() []Language {
+	langs := make([]Language, 0, len(displayNames))
+	for l := range displayNames {
+		langs = append(langs, l)
+	}
+	sort.Slice(langs, func(i, j int) bool { return langs[i] < langs[j] })
+	return langs
+}
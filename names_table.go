@@ -0,0 +1,1869 @@
+// Code generated by gen_names.go; DO NOT EDIT.
+
+package cld2
+
+// English display names, subscripted by Language.
+var languageToName = []string{
+	"English",                       // 0 en
+	"Danish",                        // 1 da
+	"Dutch",                         // 2 nl
+	"Finnish",                       // 3 fi
+	"French",                        // 4 fr
+	"German",                        // 5 de
+	"Hebrew",                        // 6 iw
+	"Italian",                       // 7 it
+	"Japanese",                      // 8 ja
+	"Korean",                        // 9 ko
+	"Norwegian",                     // 10 no
+	"Polish",                        // 11 pl
+	"Portuguese",                    // 12 pt
+	"Russian",                       // 13 ru
+	"Spanish",                       // 14 es
+	"Swedish",                       // 15 sv
+	"Chinese",                       // 16 zh
+	"Czech",                         // 17 cs
+	"Greek",                         // 18 el
+	"Icelandic",                     // 19 is
+	"Latvian",                       // 20 lv
+	"Lithuanian",                    // 21 lt
+	"Romanian",                      // 22 ro
+	"Hungarian",                     // 23 hu
+	"Estonian",                      // 24 et
+	"Ignore",                        // 25 xxx
+	"Unknown",                       // 26 un
+	"Bulgarian",                     // 27 bg
+	"Croatian",                      // 28 hr
+	"Serbian",                       // 29 sr
+	"Irish",                         // 30 ga
+	"Galician",                      // 31 gl
+	"Tagalog",                       // 32 tl
+	"Turkish",                       // 33 tr
+	"Ukrainian",                     // 34 uk
+	"Hindi",                         // 35 hi
+	"Macedonian",                    // 36 mk
+	"Bengali",                       // 37 bn
+	"Indonesian",                    // 38 id
+	"Latin",                         // 39 la
+	"Malay",                         // 40 ms
+	"Malayalam",                     // 41 ml
+	"Welsh",                         // 42 cy
+	"Nepali",                        // 43 ne
+	"Telugu",                        // 44 te
+	"Albanian",                      // 45 sq
+	"Tamil",                         // 46 ta
+	"Belarusian",                    // 47 be
+	"Javanese",                      // 48 jw
+	"Occitan",                       // 49 oc
+	"Urdu",                          // 50 ur
+	"Bihari",                        // 51 bh
+	"Gujarati",                      // 52 gu
+	"Thai",                          // 53 th
+	"Arabic",                        // 54 ar
+	"Catalan",                       // 55 ca
+	"Esperanto",                     // 56 eo
+	"Basque",                        // 57 eu
+	"Interlingua",                   // 58 ia
+	"Kannada",                       // 59 kn
+	"Punjabi",                       // 60 pa
+	"Scottish Gaelic",               // 61 gd
+	"Swahili",                       // 62 sw
+	"Slovenian",                     // 63 sl
+	"Marathi",                       // 64 mr
+	"Maltese",                       // 65 mt
+	"Vietnamese",                    // 66 vi
+	"Frisian",                       // 67 fy
+	"Slovak",                        // 68 sk
+	"Chinese (Traditional)",         // 69 zh-Hant
+	"Faroese",                       // 70 fo
+	"Sundanese",                     // 71 su
+	"Uzbek",                         // 72 uz
+	"Amharic",                       // 73 am
+	"Azerbaijani",                   // 74 az
+	"Georgian",                      // 75 ka
+	"Tigrinya",                      // 76 ti
+	"Persian",                       // 77 fa
+	"Bosnian",                       // 78 bs
+	"Sinhala",                       // 79 si
+	"Norwegian Nynorsk",             // 80 nn
+	"",                              // 81
+	"",                              // 82
+	"Xhosa",                         // 83 xh
+	"Zulu",                          // 84 zu
+	"Guarani",                       // 85 gn
+	"Sesotho",                       // 86 st
+	"Turkmen",                       // 87 tk
+	"Kyrgyz",                        // 88 ky
+	"Breton",                        // 89 br
+	"Twi",                           // 90 tw
+	"Yiddish",                       // 91 yi
+	"",                              // 92
+	"Somali",                        // 93 so
+	"Uyghur",                        // 94 ug
+	"Kurdish",                       // 95 ku
+	"Mongolian",                     // 96 mn
+	"Armenian",                      // 97 hy
+	"Lao",                           // 98 lo
+	"Sindhi",                        // 99 sd
+	"Romansh",                       // 100 rm
+	"Afrikaans",                     // 101 af
+	"Luxembourgish",                 // 102 lb
+	"Burmese",                       // 103 my
+	"Khmer",                         // 104 km
+	"Tibetan",                       // 105 bo
+	"Dhivehi",                       // 106 dv
+	"Cherokee",                      // 107 chr
+	"Syriac",                        // 108 syr
+	"Limbu",                         // 109 lif
+	"Odia",                          // 110 or
+	"Assamese",                      // 111 as
+	"Corsican",                      // 112 co
+	"Interlingue",                   // 113 ie
+	"Kazakh",                        // 114 kk
+	"Lingala",                       // 115 ln
+	"",                              // 116
+	"Pashto",                        // 117 ps
+	"Quechua",                       // 118 qu
+	"Shona",                         // 119 sn
+	"Tajik",                         // 120 tg
+	"Tatar",                         // 121 tt
+	"Tongan",                        // 122 to
+	"Yoruba",                        // 123 yo
+	"",                              // 124
+	"",                              // 125
+	"",                              // 126
+	"",                              // 127
+	"Maori",                         // 128 mi
+	"Wolof",                         // 129 wo
+	"Abkhazian",                     // 130 ab
+	"Afar",                          // 131 aa
+	"Aymara",                        // 132 ay
+	"Bashkir",                       // 133 ba
+	"Bislama",                       // 134 bi
+	"Dzongkha",                      // 135 dz
+	"Fijian",                        // 136 fj
+	"Greenlandic",                   // 137 kl
+	"Hausa",                         // 138 ha
+	"Haitian Creole",                // 139 ht
+	"Inupiaq",                       // 140 ik
+	"Inuktitut",                     // 141 iu
+	"Kashmiri",                      // 142 ks
+	"Kinyarwanda",                   // 143 rw
+	"Malagasy",                      // 144 mg
+	"Nauru",                         // 145 na
+	"Oromo",                         // 146 om
+	"Rundi",                         // 147 rn
+	"Samoan",                        // 148 sm
+	"Sango",                         // 149 sg
+	"Sanskrit",                      // 150 sa
+	"Swati",                         // 151 ss
+	"Tsonga",                        // 152 ts
+	"Tswana",                        // 153 tn
+	"Volapuk",                       // 154 vo
+	"Zhuang",                        // 155 za
+	"Khasi",                         // 156 kha
+	"Scots",                         // 157 sco
+	"Ganda",                         // 158 lg
+	"Manx",                          // 159 gv
+	"Montenegrin",                   // 160 sr-ME
+	"Akan",                          // 161 ak
+	"Igbo",                          // 162 ig
+	"Mauritian Creole",              // 163 mfe
+	"Hawaiian",                      // 164 haw
+	"Cebuano",                       // 165 ceb
+	"Ewe",                           // 166 ee
+	"Ga",                            // 167 gaa
+	"Hmong",                         // 168 hmn
+	"Krio",                          // 169 kri
+	"Lozi",                          // 170 loz
+	"Luba-Lulua",                    // 171 lua
+	"Luo",                           // 172 luo
+	"Newari",                        // 173 new
+	"Nyanja",                        // 174 ny
+	"Ossetian",                      // 175 os
+	"Pampanga",                      // 176 pam
+	"Northern Sotho",                // 177 nso
+	"Rajasthani",                    // 178 raj
+	"Seselwa Creole French",         // 179 crs
+	"Tumbuka",                       // 180 tum
+	"Venda",                         // 181 ve
+	"Waray",                         // 182 war
+	"",                              // 183
+	"",                              // 184
+	"",                              // 185
+	"",                              // 186
+	"",                              // 187
+	"",                              // 188
+	"",                              // 189
+	"",                              // 190
+	"",                              // 191
+	"",                              // 192
+	"",                              // 193
+	"",                              // 194
+	"",                              // 195
+	"",                              // 196
+	"",                              // 197
+	"",                              // 198
+	"",                              // 199
+	"",                              // 200
+	"",                              // 201
+	"",                              // 202
+	"",                              // 203
+	"",                              // 204
+	"",                              // 205
+	"",                              // 206
+	"",                              // 207
+	"",                              // 208
+	"",                              // 209
+	"",                              // 210
+	"",                              // 211
+	"",                              // 212
+	"",                              // 213
+	"",                              // 214
+	"",                              // 215
+	"",                              // 216
+	"",                              // 217
+	"",                              // 218
+	"",                              // 219
+	"",                              // 220
+	"",                              // 221
+	"",                              // 222
+	"",                              // 223
+	"",                              // 224
+	"",                              // 225
+	"",                              // 226
+	"",                              // 227
+	"",                              // 228
+	"",                              // 229
+	"",                              // 230
+	"",                              // 231
+	"",                              // 232
+	"",                              // 233
+	"",                              // 234
+	"",                              // 235
+	"",                              // 236
+	"",                              // 237
+	"",                              // 238
+	"",                              // 239
+	"",                              // 240
+	"",                              // 241
+	"",                              // 242
+	"",                              // 243
+	"",                              // 244
+	"",                              // 245
+	"",                              // 246
+	"",                              // 247
+	"",                              // 248
+	"",                              // 249
+	"",                              // 250
+	"",                              // 251
+	"",                              // 252
+	"",                              // 253
+	"",                              // 254
+	"",                              // 255
+	"",                              // 256
+	"",                              // 257
+	"",                              // 258
+	"",                              // 259
+	"",                              // 260
+	"",                              // 261
+	"",                              // 262
+	"",                              // 263
+	"",                              // 264
+	"",                              // 265
+	"",                              // 266
+	"",                              // 267
+	"",                              // 268
+	"",                              // 269
+	"",                              // 270
+	"",                              // 271
+	"",                              // 272
+	"",                              // 273
+	"",                              // 274
+	"",                              // 275
+	"",                              // 276
+	"",                              // 277
+	"",                              // 278
+	"",                              // 279
+	"",                              // 280
+	"",                              // 281
+	"",                              // 282
+	"",                              // 283
+	"",                              // 284
+	"",                              // 285
+	"",                              // 286
+	"",                              // 287
+	"",                              // 288
+	"",                              // 289
+	"",                              // 290
+	"",                              // 291
+	"",                              // 292
+	"",                              // 293
+	"",                              // 294
+	"",                              // 295
+	"",                              // 296
+	"",                              // 297
+	"",                              // 298
+	"",                              // 299
+	"",                              // 300
+	"",                              // 301
+	"",                              // 302
+	"",                              // 303
+	"",                              // 304
+	"",                              // 305
+	"",                              // 306
+	"",                              // 307
+	"",                              // 308
+	"",                              // 309
+	"",                              // 310
+	"",                              // 311
+	"",                              // 312
+	"",                              // 313
+	"",                              // 314
+	"",                              // 315
+	"",                              // 316
+	"",                              // 317
+	"",                              // 318
+	"",                              // 319
+	"",                              // 320
+	"",                              // 321
+	"",                              // 322
+	"",                              // 323
+	"",                              // 324
+	"",                              // 325
+	"",                              // 326
+	"",                              // 327
+	"",                              // 328
+	"",                              // 329
+	"",                              // 330
+	"",                              // 331
+	"",                              // 332
+	"",                              // 333
+	"",                              // 334
+	"",                              // 335
+	"",                              // 336
+	"",                              // 337
+	"",                              // 338
+	"",                              // 339
+	"",                              // 340
+	"",                              // 341
+	"",                              // 342
+	"",                              // 343
+	"",                              // 344
+	"",                              // 345
+	"",                              // 346
+	"",                              // 347
+	"",                              // 348
+	"",                              // 349
+	"",                              // 350
+	"",                              // 351
+	"",                              // 352
+	"",                              // 353
+	"",                              // 354
+	"",                              // 355
+	"",                              // 356
+	"",                              // 357
+	"",                              // 358
+	"",                              // 359
+	"",                              // 360
+	"",                              // 361
+	"",                              // 362
+	"",                              // 363
+	"",                              // 364
+	"",                              // 365
+	"",                              // 366
+	"",                              // 367
+	"",                              // 368
+	"",                              // 369
+	"",                              // 370
+	"",                              // 371
+	"",                              // 372
+	"",                              // 373
+	"",                              // 374
+	"",                              // 375
+	"",                              // 376
+	"",                              // 377
+	"",                              // 378
+	"",                              // 379
+	"",                              // 380
+	"",                              // 381
+	"",                              // 382
+	"",                              // 383
+	"",                              // 384
+	"",                              // 385
+	"",                              // 386
+	"",                              // 387
+	"",                              // 388
+	"",                              // 389
+	"",                              // 390
+	"",                              // 391
+	"",                              // 392
+	"",                              // 393
+	"",                              // 394
+	"",                              // 395
+	"",                              // 396
+	"",                              // 397
+	"",                              // 398
+	"",                              // 399
+	"",                              // 400
+	"",                              // 401
+	"",                              // 402
+	"",                              // 403
+	"",                              // 404
+	"",                              // 405
+	"",                              // 406
+	"",                              // 407
+	"",                              // 408
+	"",                              // 409
+	"",                              // 410
+	"",                              // 411
+	"",                              // 412
+	"",                              // 413
+	"",                              // 414
+	"",                              // 415
+	"",                              // 416
+	"",                              // 417
+	"",                              // 418
+	"",                              // 419
+	"",                              // 420
+	"",                              // 421
+	"",                              // 422
+	"",                              // 423
+	"",                              // 424
+	"",                              // 425
+	"",                              // 426
+	"",                              // 427
+	"",                              // 428
+	"",                              // 429
+	"",                              // 430
+	"",                              // 431
+	"",                              // 432
+	"",                              // 433
+	"",                              // 434
+	"",                              // 435
+	"",                              // 436
+	"",                              // 437
+	"",                              // 438
+	"",                              // 439
+	"",                              // 440
+	"",                              // 441
+	"",                              // 442
+	"",                              // 443
+	"",                              // 444
+	"",                              // 445
+	"",                              // 446
+	"",                              // 447
+	"",                              // 448
+	"",                              // 449
+	"",                              // 450
+	"",                              // 451
+	"",                              // 452
+	"",                              // 453
+	"",                              // 454
+	"",                              // 455
+	"",                              // 456
+	"",                              // 457
+	"",                              // 458
+	"",                              // 459
+	"",                              // 460
+	"",                              // 461
+	"",                              // 462
+	"",                              // 463
+	"",                              // 464
+	"",                              // 465
+	"",                              // 466
+	"",                              // 467
+	"",                              // 468
+	"",                              // 469
+	"",                              // 470
+	"",                              // 471
+	"",                              // 472
+	"",                              // 473
+	"",                              // 474
+	"",                              // 475
+	"",                              // 476
+	"",                              // 477
+	"",                              // 478
+	"",                              // 479
+	"",                              // 480
+	"",                              // 481
+	"",                              // 482
+	"",                              // 483
+	"",                              // 484
+	"",                              // 485
+	"",                              // 486
+	"",                              // 487
+	"",                              // 488
+	"",                              // 489
+	"",                              // 490
+	"",                              // 491
+	"",                              // 492
+	"",                              // 493
+	"",                              // 494
+	"",                              // 495
+	"",                              // 496
+	"",                              // 497
+	"",                              // 498
+	"",                              // 499
+	"",                              // 500
+	"",                              // 501
+	"",                              // 502
+	"",                              // 503
+	"",                              // 504
+	"",                              // 505
+	"South Ndebele",                 // 506 nr
+	"Bork Bork Bork",                // 507 zzb
+	"Pig Latin",                     // 508 zzp
+	"Hacker",                        // 509 zzh
+	"Klingon",                       // 510 tlh
+	"Elmer Fudd",                    // 511 zze
+	"Common script",                 // 512 xx-Zyyy
+	"Latin script",                  // 513 xx-Latn
+	"Greek script",                  // 514 xx-Grek
+	"Cyrillic script",               // 515 xx-Cyrl
+	"Armenian script",               // 516 xx-Armn
+	"Hebrew script",                 // 517 xx-Hebr
+	"Arabic script",                 // 518 xx-Arab
+	"Syriac script",                 // 519 xx-Syrc
+	"Thaana script",                 // 520 xx-Thaa
+	"Devanagari script",             // 521 xx-Deva
+	"Bengali script",                // 522 xx-Beng
+	"Gurmukhi script",               // 523 xx-Guru
+	"Gujarati script",               // 524 xx-Gujr
+	"Oriya script",                  // 525 xx-Orya
+	"Tamil script",                  // 526 xx-Taml
+	"Telugu script",                 // 527 xx-Telu
+	"Kannada script",                // 528 xx-Knda
+	"Malayalam script",              // 529 xx-Mlym
+	"Sinhala script",                // 530 xx-Sinh
+	"Thai script",                   // 531 xx-Thai
+	"Lao script",                    // 532 xx-Laoo
+	"Tibetan script",                // 533 xx-Tibt
+	"Myanmar script",                // 534 xx-Mymr
+	"Georgian script",               // 535 xx-Geor
+	"Hangul script",                 // 536 xx-Hang
+	"Ethiopic script",               // 537 xx-Ethi
+	"Cherokee script",               // 538 xx-Cher
+	"Canadian Aboriginal script",    // 539 xx-Cans
+	"Ogham script",                  // 540 xx-Ogam
+	"Runic script",                  // 541 xx-Runr
+	"Khmer script",                  // 542 xx-Khmr
+	"Mongolian script",              // 543 xx-Mong
+	"Hiragana script",               // 544 xx-Hira
+	"Katakana script",               // 545 xx-Kana
+	"Bopomofo script",               // 546 xx-Bopo
+	"Han script",                    // 547 xx-Hani
+	"Yi script",                     // 548 xx-Yiii
+	"Old Italic script",             // 549 xx-Ital
+	"Gothic script",                 // 550 xx-Goth
+	"Deseret script",                // 551 xx-Dsrt
+	"Inherited script",              // 552 xx-Qaai
+	"Tagalog script",                // 553 xx-Tglg
+	"Hanunoo script",                // 554 xx-Hano
+	"Buhid script",                  // 555 xx-Buhd
+	"Tagbanwa script",               // 556 xx-Tagb
+	"Limbu script",                  // 557 xx-Limb
+	"Tai Le script",                 // 558 xx-Tale
+	"Linear B script",               // 559 xx-Linb
+	"Ugaritic script",               // 560 xx-Ugar
+	"Shavian script",                // 561 xx-Shaw
+	"Osmanya script",                // 562 xx-Osma
+	"Cypriot script",                // 563 xx-Cprt
+	"Braille script",                // 564 xx-Brai
+	"Buginese script",               // 565 xx-Bugi
+	"Coptic script",                 // 566 xx-Copt
+	"New Tai Lue script",            // 567 xx-Talu
+	"Glagolitic script",             // 568 xx-Glag
+	"Tifinagh script",               // 569 xx-Tfng
+	"Syloti Nagri script",           // 570 xx-Sylo
+	"Old Persian script",            // 571 xx-Xpeo
+	"Kharoshthi script",             // 572 xx-Khar
+	"Balinese script",               // 573 xx-Bali
+	"Cuneiform script",              // 574 xx-Xsux
+	"Phoenician script",             // 575 xx-Phnx
+	"Phags Pa script",               // 576 xx-Phag
+	"Nko script",                    // 577 xx-Nkoo
+	"Sundanese script",              // 578 xx-Sund
+	"Lepcha script",                 // 579 xx-Lepc
+	"Ol Chiki script",               // 580 xx-Olck
+	"Vai script",                    // 581 xx-Vaii
+	"Saurashtra script",             // 582 xx-Saur
+	"Kayah Li script",               // 583 xx-Kali
+	"Rejang script",                 // 584 xx-Rjng
+	"Lycian script",                 // 585 xx-Lyci
+	"Carian script",                 // 586 xx-Cari
+	"Lydian script",                 // 587 xx-Lydi
+	"Cham script",                   // 588 xx-Cham
+	"Tai Tham script",               // 589 xx-Lana
+	"Tai Viet script",               // 590 xx-Tavt
+	"Avestan script",                // 591 xx-Avst
+	"Egyptian Hieroglyphs script",   // 592 xx-Egyp
+	"Samaritan script",              // 593 xx-Samr
+	"Lisu script",                   // 594 xx-Lisu
+	"Bamum script",                  // 595 xx-Bamu
+	"Javanese script",               // 596 xx-Java
+	"Meetei Mayek script",           // 597 xx-Mtei
+	"Imperial Aramaic script",       // 598 xx-Armi
+	"Old South Arabian script",      // 599 xx-Sarb
+	"Inscriptional Parthian script", // 600 xx-Prti
+	"Inscriptional Pahlavi script",  // 601 xx-Phli
+	"Old Turkic script",             // 602 xx-Orkh
+	"Kaithi script",                 // 603 xx-Kthi
+	"Batak script",                  // 604 xx-Batk
+	"Brahmi script",                 // 605 xx-Brah
+	"Mandaic script",                // 606 xx-Mand
+	"Chakma script",                 // 607 xx-Cakm
+	"Meroitic Cursive script",       // 608 xx-Merc
+	"Meroitic Hieroglyphs script",   // 609 xx-Mero
+	"Miao script",                   // 610 xx-Plrd
+	"Sharada script",                // 611 xx-Shrd
+	"Sora Sompeng script",           // 612 xx-Sora
+	"Takri script",                  // 613 xx-Takr
+}
+
+// Names of each language in the language itself, subscripted by Language.
+var languageToNativeName = []string{
+	"English",          // 0 en
+	"dansk",            // 1 da
+	"Nederlands",       // 2 nl
+	"suomi",            // 3 fi
+	"français",         // 4 fr
+	"Deutsch",          // 5 de
+	"עברית",            // 6 iw
+	"italiano",         // 7 it
+	"日本語",              // 8 ja
+	"한국어",              // 9 ko
+	"norsk",            // 10 no
+	"polski",           // 11 pl
+	"português",        // 12 pt
+	"русский",          // 13 ru
+	"español",          // 14 es
+	"svenska",          // 15 sv
+	"中文",               // 16 zh
+	"čeština",          // 17 cs
+	"Ελληνικά",         // 18 el
+	"íslenska",         // 19 is
+	"latviešu",         // 20 lv
+	"lietuvių",         // 21 lt
+	"română",           // 22 ro
+	"magyar",           // 23 hu
+	"eesti",            // 24 et
+	"",                 // 25 xxx
+	"",                 // 26 un
+	"български",        // 27 bg
+	"hrvatski",         // 28 hr
+	"српски",           // 29 sr
+	"Gaeilge",          // 30 ga
+	"galego",           // 31 gl
+	"Tagalog",          // 32 tl
+	"Türkçe",           // 33 tr
+	"українська",       // 34 uk
+	"हिन्दी",           // 35 hi
+	"македонски",       // 36 mk
+	"বাংলা",            // 37 bn
+	"Bahasa Indonesia", // 38 id
+	"Latina",           // 39 la
+	"Bahasa Melayu",    // 40 ms
+	"മലയാളം",           // 41 ml
+	"Cymraeg",          // 42 cy
+	"नेपाली",           // 43 ne
+	"తెలుగు",           // 44 te
+	"shqip",            // 45 sq
+	"தமிழ்",            // 46 ta
+	"беларуская",       // 47 be
+	"Basa Jawa",        // 48 jw
+	"occitan",          // 49 oc
+	"اردو",             // 50 ur
+	"",                 // 51 bh
+	"ગુજરાતી",          // 52 gu
+	"ไทย",              // 53 th
+	"العربية",          // 54 ar
+	"català",           // 55 ca
+	"Esperanto",        // 56 eo
+	"euskara",          // 57 eu
+	"Interlingua",      // 58 ia
+	"ಕನ್ನಡ",            // 59 kn
+	"ਪੰਜਾਬੀ",           // 60 pa
+	"Gàidhlig",         // 61 gd
+	"Kiswahili",        // 62 sw
+	"slovenščina",      // 63 sl
+	"मराठी",            // 64 mr
+	"Malti",            // 65 mt
+	"Tiếng Việt",       // 66 vi
+	"Frysk",            // 67 fy
+	"slovenčina",       // 68 sk
+	"繁體中文",             // 69 zh-Hant
+	"føroyskt",         // 70 fo
+	"Basa Sunda",       // 71 su
+	"oʻzbekcha",        // 72 uz
+	"አማርኛ",             // 73 am
+	"azərbaycan",       // 74 az
+	"ქართული",          // 75 ka
+	"ትግርኛ",             // 76 ti
+	"فارسی",            // 77 fa
+	"bosanski",         // 78 bs
+	"සිංහල",            // 79 si
+	"nynorsk",          // 80 nn
+	"",                 // 81
+	"",                 // 82
+	"isiXhosa",         // 83 xh
+	"isiZulu",          // 84 zu
+	"avañeʼẽ",          // 85 gn
+	"Sesotho",          // 86 st
+	"türkmen dili",     // 87 tk
+	"кыргызча",         // 88 ky
+	"brezhoneg",        // 89 br
+	"",                 // 90 tw
+	"ייִדיש",           // 91 yi
+	"",                 // 92
+	"Soomaali",         // 93 so
+	"ئۇيغۇرچە",         // 94 ug
+	"Kurdî",            // 95 ku
+	"монгол",           // 96 mn
+	"հայերեն",          // 97 hy
+	"ລາວ",              // 98 lo
+	"سنڌي",             // 99 sd
+	"rumantsch",        // 100 rm
+	"Afrikaans",        // 101 af
+	"Lëtzebuergesch",   // 102 lb
+	"မြန်မာ",           // 103 my
+	"ខ្មែរ",            // 104 km
+	"བོད་སྐད་",         // 105 bo
+	"ދިވެހި",           // 106 dv
+	"ᏣᎳᎩ",              // 107 chr
+	"",                 // 108 syr
+	"",                 // 109 lif
+	"ଓଡ଼ିଆ",            // 110 or
+	"অসমীয়া",          // 111 as
+	"corsu",            // 112 co
+	"",                 // 113 ie
+	"қазақ тілі",       // 114 kk
+	"lingála",          // 115 ln
+	"",                 // 116
+	"پښتو",             // 117 ps
+	"Runasimi",         // 118 qu
+	"chiShona",         // 119 sn
+	"тоҷикӣ",           // 120 tg
+	"татар",            // 121 tt
+	"lea fakatonga",    // 122 to
+	"Èdè Yorùbá",       // 123 yo
+	"",                 // 124
+	"",                 // 125
+	"",                 // 126
+	"",                 // 127
+	"te reo Māori",     // 128 mi
+	"Wolof",            // 129 wo
+	"аҧсуа",            // 130 ab
+	"",                 // 131 aa
+	"",                 // 132 ay
+	"башҡорт",          // 133 ba
+	"",                 // 134 bi
+	"རྫོང་ཁ",           // 135 dz
+	"vosa Vakaviti",    // 136 fj
+	"kalaallisut",      // 137 kl
+	"Hausa",            // 138 ha
+	"Kreyòl ayisyen",   // 139 ht
+	"",                 // 140 ik
+	"ᐃᓄᒃᑎᑐᑦ",           // 141 iu
+	"",                 // 142 ks
+	"Kinyarwanda",      // 143 rw
+	"Malagasy",         // 144 mg
+	"",                 // 145 na
+	"Oromoo",           // 146 om
+	"Ikirundi",         // 147 rn
+	"Gagana Samoa",     // 148 sm
+	"Sängö",            // 149 sg
+	"संस्कृतम्",        // 150 sa
+	"siSwati",          // 151 ss
+	"Xitsonga",         // 152 ts
+	"Setswana",         // 153 tn
+	"",                 // 154 vo
+	"",                 // 155 za
+	"",                 // 156 kha
+	"",                 // 157 sco
+	"Luganda",          // 158 lg
+	"Gaelg",            // 159 gv
+	"crnogorski",       // 160 sr-ME
+	"",                 // 161 ak
+	"Igbo",             // 162 ig
+	"",                 // 163 mfe
+	"ʻŌlelo Hawaiʻi",   // 164 haw
+	"Cebuano",          // 165 ceb
+	"Eʋegbe",           // 166 ee
+	"",                 // 167 gaa
+	"",                 // 168 hmn
+	"",                 // 169 kri
+	"",                 // 170 loz
+	"",                 // 171 lua
+	"",                 // 172 luo
+	"",                 // 173 new
+	"Chichewa",         // 174 ny
+	"ирон",             // 175 os
+	"",                 // 176 pam
+	"Sesotho sa Leboa", // 177 nso
+	"",                 // 178 raj
+	"",                 // 179 crs
+	"",                 // 180 tum
+	"Tshivenḓa",        // 181 ve
+	"",                 // 182 war
+	"",                 // 183
+	"",                 // 184
+	"",                 // 185
+	"",                 // 186
+	"",                 // 187
+	"",                 // 188
+	"",                 // 189
+	"",                 // 190
+	"",                 // 191
+	"",                 // 192
+	"",                 // 193
+	"",                 // 194
+	"",                 // 195
+	"",                 // 196
+	"",                 // 197
+	"",                 // 198
+	"",                 // 199
+	"",                 // 200
+	"",                 // 201
+	"",                 // 202
+	"",                 // 203
+	"",                 // 204
+	"",                 // 205
+	"",                 // 206
+	"",                 // 207
+	"",                 // 208
+	"",                 // 209
+	"",                 // 210
+	"",                 // 211
+	"",                 // 212
+	"",                 // 213
+	"",                 // 214
+	"",                 // 215
+	"",                 // 216
+	"",                 // 217
+	"",                 // 218
+	"",                 // 219
+	"",                 // 220
+	"",                 // 221
+	"",                 // 222
+	"",                 // 223
+	"",                 // 224
+	"",                 // 225
+	"",                 // 226
+	"",                 // 227
+	"",                 // 228
+	"",                 // 229
+	"",                 // 230
+	"",                 // 231
+	"",                 // 232
+	"",                 // 233
+	"",                 // 234
+	"",                 // 235
+	"",                 // 236
+	"",                 // 237
+	"",                 // 238
+	"",                 // 239
+	"",                 // 240
+	"",                 // 241
+	"",                 // 242
+	"",                 // 243
+	"",                 // 244
+	"",                 // 245
+	"",                 // 246
+	"",                 // 247
+	"",                 // 248
+	"",                 // 249
+	"",                 // 250
+	"",                 // 251
+	"",                 // 252
+	"",                 // 253
+	"",                 // 254
+	"",                 // 255
+	"",                 // 256
+	"",                 // 257
+	"",                 // 258
+	"",                 // 259
+	"",                 // 260
+	"",                 // 261
+	"",                 // 262
+	"",                 // 263
+	"",                 // 264
+	"",                 // 265
+	"",                 // 266
+	"",                 // 267
+	"",                 // 268
+	"",                 // 269
+	"",                 // 270
+	"",                 // 271
+	"",                 // 272
+	"",                 // 273
+	"",                 // 274
+	"",                 // 275
+	"",                 // 276
+	"",                 // 277
+	"",                 // 278
+	"",                 // 279
+	"",                 // 280
+	"",                 // 281
+	"",                 // 282
+	"",                 // 283
+	"",                 // 284
+	"",                 // 285
+	"",                 // 286
+	"",                 // 287
+	"",                 // 288
+	"",                 // 289
+	"",                 // 290
+	"",                 // 291
+	"",                 // 292
+	"",                 // 293
+	"",                 // 294
+	"",                 // 295
+	"",                 // 296
+	"",                 // 297
+	"",                 // 298
+	"",                 // 299
+	"",                 // 300
+	"",                 // 301
+	"",                 // 302
+	"",                 // 303
+	"",                 // 304
+	"",                 // 305
+	"",                 // 306
+	"",                 // 307
+	"",                 // 308
+	"",                 // 309
+	"",                 // 310
+	"",                 // 311
+	"",                 // 312
+	"",                 // 313
+	"",                 // 314
+	"",                 // 315
+	"",                 // 316
+	"",                 // 317
+	"",                 // 318
+	"",                 // 319
+	"",                 // 320
+	"",                 // 321
+	"",                 // 322
+	"",                 // 323
+	"",                 // 324
+	"",                 // 325
+	"",                 // 326
+	"",                 // 327
+	"",                 // 328
+	"",                 // 329
+	"",                 // 330
+	"",                 // 331
+	"",                 // 332
+	"",                 // 333
+	"",                 // 334
+	"",                 // 335
+	"",                 // 336
+	"",                 // 337
+	"",                 // 338
+	"",                 // 339
+	"",                 // 340
+	"",                 // 341
+	"",                 // 342
+	"",                 // 343
+	"",                 // 344
+	"",                 // 345
+	"",                 // 346
+	"",                 // 347
+	"",                 // 348
+	"",                 // 349
+	"",                 // 350
+	"",                 // 351
+	"",                 // 352
+	"",                 // 353
+	"",                 // 354
+	"",                 // 355
+	"",                 // 356
+	"",                 // 357
+	"",                 // 358
+	"",                 // 359
+	"",                 // 360
+	"",                 // 361
+	"",                 // 362
+	"",                 // 363
+	"",                 // 364
+	"",                 // 365
+	"",                 // 366
+	"",                 // 367
+	"",                 // 368
+	"",                 // 369
+	"",                 // 370
+	"",                 // 371
+	"",                 // 372
+	"",                 // 373
+	"",                 // 374
+	"",                 // 375
+	"",                 // 376
+	"",                 // 377
+	"",                 // 378
+	"",                 // 379
+	"",                 // 380
+	"",                 // 381
+	"",                 // 382
+	"",                 // 383
+	"",                 // 384
+	"",                 // 385
+	"",                 // 386
+	"",                 // 387
+	"",                 // 388
+	"",                 // 389
+	"",                 // 390
+	"",                 // 391
+	"",                 // 392
+	"",                 // 393
+	"",                 // 394
+	"",                 // 395
+	"",                 // 396
+	"",                 // 397
+	"",                 // 398
+	"",                 // 399
+	"",                 // 400
+	"",                 // 401
+	"",                 // 402
+	"",                 // 403
+	"",                 // 404
+	"",                 // 405
+	"",                 // 406
+	"",                 // 407
+	"",                 // 408
+	"",                 // 409
+	"",                 // 410
+	"",                 // 411
+	"",                 // 412
+	"",                 // 413
+	"",                 // 414
+	"",                 // 415
+	"",                 // 416
+	"",                 // 417
+	"",                 // 418
+	"",                 // 419
+	"",                 // 420
+	"",                 // 421
+	"",                 // 422
+	"",                 // 423
+	"",                 // 424
+	"",                 // 425
+	"",                 // 426
+	"",                 // 427
+	"",                 // 428
+	"",                 // 429
+	"",                 // 430
+	"",                 // 431
+	"",                 // 432
+	"",                 // 433
+	"",                 // 434
+	"",                 // 435
+	"",                 // 436
+	"",                 // 437
+	"",                 // 438
+	"",                 // 439
+	"",                 // 440
+	"",                 // 441
+	"",                 // 442
+	"",                 // 443
+	"",                 // 444
+	"",                 // 445
+	"",                 // 446
+	"",                 // 447
+	"",                 // 448
+	"",                 // 449
+	"",                 // 450
+	"",                 // 451
+	"",                 // 452
+	"",                 // 453
+	"",                 // 454
+	"",                 // 455
+	"",                 // 456
+	"",                 // 457
+	"",                 // 458
+	"",                 // 459
+	"",                 // 460
+	"",                 // 461
+	"",                 // 462
+	"",                 // 463
+	"",                 // 464
+	"",                 // 465
+	"",                 // 466
+	"",                 // 467
+	"",                 // 468
+	"",                 // 469
+	"",                 // 470
+	"",                 // 471
+	"",                 // 472
+	"",                 // 473
+	"",                 // 474
+	"",                 // 475
+	"",                 // 476
+	"",                 // 477
+	"",                 // 478
+	"",                 // 479
+	"",                 // 480
+	"",                 // 481
+	"",                 // 482
+	"",                 // 483
+	"",                 // 484
+	"",                 // 485
+	"",                 // 486
+	"",                 // 487
+	"",                 // 488
+	"",                 // 489
+	"",                 // 490
+	"",                 // 491
+	"",                 // 492
+	"",                 // 493
+	"",                 // 494
+	"",                 // 495
+	"",                 // 496
+	"",                 // 497
+	"",                 // 498
+	"",                 // 499
+	"",                 // 500
+	"",                 // 501
+	"",                 // 502
+	"",                 // 503
+	"",                 // 504
+	"",                 // 505
+	"isiNdebele",       // 506 nr
+	"",                 // 507 zzb
+	"",                 // 508 zzp
+	"",                 // 509 zzh
+	"",                 // 510 tlh
+	"",                 // 511 zze
+	"",                 // 512 xx-Zyyy
+	"",                 // 513 xx-Latn
+	"",                 // 514 xx-Grek
+	"",                 // 515 xx-Cyrl
+	"",                 // 516 xx-Armn
+	"",                 // 517 xx-Hebr
+	"",                 // 518 xx-Arab
+	"",                 // 519 xx-Syrc
+	"",                 // 520 xx-Thaa
+	"",                 // 521 xx-Deva
+	"",                 // 522 xx-Beng
+	"",                 // 523 xx-Guru
+	"",                 // 524 xx-Gujr
+	"",                 // 525 xx-Orya
+	"",                 // 526 xx-Taml
+	"",                 // 527 xx-Telu
+	"",                 // 528 xx-Knda
+	"",                 // 529 xx-Mlym
+	"",                 // 530 xx-Sinh
+	"",                 // 531 xx-Thai
+	"",                 // 532 xx-Laoo
+	"",                 // 533 xx-Tibt
+	"",                 // 534 xx-Mymr
+	"",                 // 535 xx-Geor
+	"",                 // 536 xx-Hang
+	"",                 // 537 xx-Ethi
+	"",                 // 538 xx-Cher
+	"",                 // 539 xx-Cans
+	"",                 // 540 xx-Ogam
+	"",                 // 541 xx-Runr
+	"",                 // 542 xx-Khmr
+	"",                 // 543 xx-Mong
+	"",                 // 544 xx-Hira
+	"",                 // 545 xx-Kana
+	"",                 // 546 xx-Bopo
+	"",                 // 547 xx-Hani
+	"",                 // 548 xx-Yiii
+	"",                 // 549 xx-Ital
+	"",                 // 550 xx-Goth
+	"",                 // 551 xx-Dsrt
+	"",                 // 552 xx-Qaai
+	"",                 // 553 xx-Tglg
+	"",                 // 554 xx-Hano
+	"",                 // 555 xx-Buhd
+	"",                 // 556 xx-Tagb
+	"",                 // 557 xx-Limb
+	"",                 // 558 xx-Tale
+	"",                 // 559 xx-Linb
+	"",                 // 560 xx-Ugar
+	"",                 // 561 xx-Shaw
+	"",                 // 562 xx-Osma
+	"",                 // 563 xx-Cprt
+	"",                 // 564 xx-Brai
+	"",                 // 565 xx-Bugi
+	"",                 // 566 xx-Copt
+	"",                 // 567 xx-Talu
+	"",                 // 568 xx-Glag
+	"",                 // 569 xx-Tfng
+	"",                 // 570 xx-Sylo
+	"",                 // 571 xx-Xpeo
+	"",                 // 572 xx-Khar
+	"",                 // 573 xx-Bali
+	"",                 // 574 xx-Xsux
+	"",                 // 575 xx-Phnx
+	"",                 // 576 xx-Phag
+	"",                 // 577 xx-Nkoo
+	"",                 // 578 xx-Sund
+	"",                 // 579 xx-Lepc
+	"",                 // 580 xx-Olck
+	"",                 // 581 xx-Vaii
+	"",                 // 582 xx-Saur
+	"",                 // 583 xx-Kali
+	"",                 // 584 xx-Rjng
+	"",                 // 585 xx-Lyci
+	"",                 // 586 xx-Cari
+	"",                 // 587 xx-Lydi
+	"",                 // 588 xx-Cham
+	"",                 // 589 xx-Lana
+	"",                 // 590 xx-Tavt
+	"",                 // 591 xx-Avst
+	"",                 // 592 xx-Egyp
+	"",                 // 593 xx-Samr
+	"",                 // 594 xx-Lisu
+	"",                 // 595 xx-Bamu
+	"",                 // 596 xx-Java
+	"",                 // 597 xx-Mtei
+	"",                 // 598 xx-Armi
+	"",                 // 599 xx-Sarb
+	"",                 // 600 xx-Prti
+	"",                 // 601 xx-Phli
+	"",                 // 602 xx-Orkh
+	"",                 // 603 xx-Kthi
+	"",                 // 604 xx-Batk
+	"",                 // 605 xx-Brah
+	"",                 // 606 xx-Mand
+	"",                 // 607 xx-Cakm
+	"",                 // 608 xx-Merc
+	"",                 // 609 xx-Mero
+	"",                 // 610 xx-Plrd
+	"",                 // 611 xx-Shrd
+	"",                 // 612 xx-Sora
+	"",                 // 613 xx-Takr
+}
+
+// Display names keyed by UI language, then by named language.
+var displayNames = map[Language]map[Language]string{
+	5: { // de
+		0:   "Englisch",                  // en
+		1:   "Dänisch",                   // da
+		2:   "Niederländisch",            // nl
+		3:   "Finnisch",                  // fi
+		4:   "Französisch",               // fr
+		5:   "Deutsch",                   // de
+		6:   "Hebräisch",                 // iw
+		7:   "Italienisch",               // it
+		8:   "Japanisch",                 // ja
+		9:   "Koreanisch",                // ko
+		10:  "Norwegisch",                // no
+		11:  "Polnisch",                  // pl
+		12:  "Portugiesisch",             // pt
+		13:  "Russisch",                  // ru
+		14:  "Spanisch",                  // es
+		15:  "Schwedisch",                // sv
+		16:  "Chinesisch",                // zh
+		17:  "Tschechisch",               // cs
+		18:  "Griechisch",                // el
+		19:  "Isländisch",                // is
+		20:  "Lettisch",                  // lv
+		21:  "Litauisch",                 // lt
+		22:  "Rumänisch",                 // ro
+		23:  "Ungarisch",                 // hu
+		24:  "Estnisch",                  // et
+		27:  "Bulgarisch",                // bg
+		28:  "Kroatisch",                 // hr
+		29:  "Serbisch",                  // sr
+		30:  "Irisch",                    // ga
+		31:  "Galicisch",                 // gl
+		32:  "Tagalog",                   // tl
+		33:  "Türkisch",                  // tr
+		34:  "Ukrainisch",                // uk
+		35:  "Hindi",                     // hi
+		36:  "Mazedonisch",               // mk
+		37:  "Bengalisch",                // bn
+		38:  "Indonesisch",               // id
+		39:  "Latein",                    // la
+		40:  "Malaiisch",                 // ms
+		42:  "Walisisch",                 // cy
+		45:  "Albanisch",                 // sq
+		46:  "Tamil",                     // ta
+		47:  "Belarussisch",              // be
+		50:  "Urdu",                      // ur
+		53:  "Thailändisch",              // th
+		54:  "Arabisch",                  // ar
+		55:  "Katalanisch",               // ca
+		56:  "Esperanto",                 // eo
+		57:  "Baskisch",                  // eu
+		62:  "Suaheli",                   // sw
+		63:  "Slowenisch",                // sl
+		66:  "Vietnamesisch",             // vi
+		68:  "Slowakisch",                // sk
+		69:  "Chinesisch (traditionell)", // zh-Hant
+		74:  "Aserbaidschanisch",         // az
+		75:  "Georgisch",                 // ka
+		77:  "Persisch",                  // fa
+		78:  "Bosnisch",                  // bs
+		80:  "Norwegisch (Nynorsk)",      // nn
+		97:  "Armenisch",                 // hy
+		101: "Afrikaans",                 // af
+		114: "Kasachisch",                // kk
+	},
+	4: { // fr
+		0:   "anglais",              // en
+		1:   "danois",               // da
+		2:   "néerlandais",          // nl
+		3:   "finnois",              // fi
+		4:   "français",             // fr
+		5:   "allemand",             // de
+		6:   "hébreu",               // iw
+		7:   "italien",              // it
+		8:   "japonais",             // ja
+		9:   "coréen",               // ko
+		10:  "norvégien",            // no
+		11:  "polonais",             // pl
+		12:  "portugais",            // pt
+		13:  "russe",                // ru
+		14:  "espagnol",             // es
+		15:  "suédois",              // sv
+		16:  "chinois",              // zh
+		17:  "tchèque",              // cs
+		18:  "grec",                 // el
+		19:  "islandais",            // is
+		20:  "letton",               // lv
+		21:  "lituanien",            // lt
+		22:  "roumain",              // ro
+		23:  "hongrois",             // hu
+		24:  "estonien",             // et
+		27:  "bulgare",              // bg
+		28:  "croate",               // hr
+		29:  "serbe",                // sr
+		30:  "irlandais",            // ga
+		31:  "galicien",             // gl
+		32:  "tagalog",              // tl
+		33:  "turc",                 // tr
+		34:  "ukrainien",            // uk
+		35:  "hindi",                // hi
+		36:  "macédonien",           // mk
+		37:  "bengali",              // bn
+		38:  "indonésien",           // id
+		39:  "latin",                // la
+		40:  "malais",               // ms
+		42:  "gallois",              // cy
+		45:  "albanais",             // sq
+		46:  "tamoul",               // ta
+		47:  "biélorusse",           // be
+		50:  "ourdou",               // ur
+		53:  "thaï",                 // th
+		54:  "arabe",                // ar
+		55:  "catalan",              // ca
+		56:  "espéranto",            // eo
+		57:  "basque",               // eu
+		62:  "swahili",              // sw
+		63:  "slovène",              // sl
+		66:  "vietnamien",           // vi
+		68:  "slovaque",             // sk
+		69:  "chinois traditionnel", // zh-Hant
+		74:  "azerbaïdjanais",       // az
+		75:  "géorgien",             // ka
+		77:  "persan",               // fa
+		78:  "bosniaque",            // bs
+		80:  "norvégien nynorsk",    // nn
+		97:  "arménien",             // hy
+		101: "afrikaans",            // af
+		114: "kazakh",               // kk
+	},
+	14: { // es
+		0:   "inglés",            // en
+		1:   "danés",             // da
+		2:   "neerlandés",        // nl
+		3:   "finés",             // fi
+		4:   "francés",           // fr
+		5:   "alemán",            // de
+		6:   "hebreo",            // iw
+		7:   "italiano",          // it
+		8:   "japonés",           // ja
+		9:   "coreano",           // ko
+		10:  "noruego",           // no
+		11:  "polaco",            // pl
+		12:  "portugués",         // pt
+		13:  "ruso",              // ru
+		14:  "español",           // es
+		15:  "sueco",             // sv
+		16:  "chino",             // zh
+		17:  "checo",             // cs
+		18:  "griego",            // el
+		19:  "islandés",          // is
+		20:  "letón",             // lv
+		21:  "lituano",           // lt
+		22:  "rumano",            // ro
+		23:  "húngaro",           // hu
+		24:  "estonio",           // et
+		27:  "búlgaro",           // bg
+		28:  "croata",            // hr
+		29:  "serbio",            // sr
+		30:  "irlandés",          // ga
+		31:  "gallego",           // gl
+		32:  "tagalo",            // tl
+		33:  "turco",             // tr
+		34:  "ucraniano",         // uk
+		35:  "hindi",             // hi
+		36:  "macedonio",         // mk
+		37:  "bengalí",           // bn
+		38:  "indonesio",         // id
+		39:  "latín",             // la
+		40:  "malayo",            // ms
+		42:  "galés",             // cy
+		45:  "albanés",           // sq
+		46:  "tamil",             // ta
+		47:  "bielorruso",        // be
+		50:  "urdu",              // ur
+		53:  "tailandés",         // th
+		54:  "árabe",             // ar
+		55:  "catalán",           // ca
+		56:  "esperanto",         // eo
+		57:  "euskera",           // eu
+		62:  "suajili",           // sw
+		63:  "esloveno",          // sl
+		66:  "vietnamita",        // vi
+		68:  "eslovaco",          // sk
+		69:  "chino tradicional", // zh-Hant
+		74:  "azerí",             // az
+		75:  "georgiano",         // ka
+		77:  "persa",             // fa
+		78:  "bosnio",            // bs
+		80:  "noruego nynorsk",   // nn
+		97:  "armenio",           // hy
+		101: "afrikáans",         // af
+		114: "kazajo",            // kk
+	},
+	8: { // ja
+		0:   "英語",              // en
+		1:   "デンマーク語",          // da
+		2:   "オランダ語",           // nl
+		3:   "フィンランド語",         // fi
+		4:   "フランス語",           // fr
+		5:   "ドイツ語",            // de
+		6:   "ヘブライ語",           // iw
+		7:   "イタリア語",           // it
+		8:   "日本語",             // ja
+		9:   "韓国語",             // ko
+		10:  "ノルウェー語",          // no
+		11:  "ポーランド語",          // pl
+		12:  "ポルトガル語",          // pt
+		13:  "ロシア語",            // ru
+		14:  "スペイン語",           // es
+		15:  "スウェーデン語",         // sv
+		16:  "中国語",             // zh
+		17:  "チェコ語",            // cs
+		18:  "ギリシャ語",           // el
+		19:  "アイスランド語",         // is
+		20:  "ラトビア語",           // lv
+		21:  "リトアニア語",          // lt
+		22:  "ルーマニア語",          // ro
+		23:  "ハンガリー語",          // hu
+		24:  "エストニア語",          // et
+		27:  "ブルガリア語",          // bg
+		28:  "クロアチア語",          // hr
+		29:  "セルビア語",           // sr
+		30:  "アイルランド語",         // ga
+		31:  "ガリシア語",           // gl
+		32:  "タガログ語",           // tl
+		33:  "トルコ語",            // tr
+		34:  "ウクライナ語",          // uk
+		35:  "ヒンディー語",          // hi
+		36:  "マケドニア語",          // mk
+		37:  "ベンガル語",           // bn
+		38:  "インドネシア語",         // id
+		39:  "ラテン語",            // la
+		40:  "マレー語",            // ms
+		42:  "ウェールズ語",          // cy
+		45:  "アルバニア語",          // sq
+		46:  "タミル語",            // ta
+		47:  "ベラルーシ語",          // be
+		50:  "ウルドゥー語",          // ur
+		53:  "タイ語",             // th
+		54:  "アラビア語",           // ar
+		55:  "カタルーニャ語",         // ca
+		56:  "エスペラント語",         // eo
+		57:  "バスク語",            // eu
+		62:  "スワヒリ語",           // sw
+		63:  "スロベニア語",          // sl
+		66:  "ベトナム語",           // vi
+		68:  "スロバキア語",          // sk
+		69:  "中国語 (繁体字)",       // zh-Hant
+		74:  "アゼルバイジャン語",       // az
+		75:  "ジョージア語",          // ka
+		77:  "ペルシア語",           // fa
+		78:  "ボスニア語",           // bs
+		80:  "ノルウェー語 (ニーノシュク)", // nn
+		97:  "アルメニア語",          // hy
+		101: "アフリカーンス語",        // af
+		114: "カザフ語",            // kk
+	},
+	16: { // zh
+		0:   "英语",      // en
+		1:   "丹麦语",     // da
+		2:   "荷兰语",     // nl
+		3:   "芬兰语",     // fi
+		4:   "法语",      // fr
+		5:   "德语",      // de
+		6:   "希伯来语",    // iw
+		7:   "意大利语",    // it
+		8:   "日语",      // ja
+		9:   "韩语",      // ko
+		10:  "挪威语",     // no
+		11:  "波兰语",     // pl
+		12:  "葡萄牙语",    // pt
+		13:  "俄语",      // ru
+		14:  "西班牙语",    // es
+		15:  "瑞典语",     // sv
+		16:  "中文",      // zh
+		17:  "捷克语",     // cs
+		18:  "希腊语",     // el
+		19:  "冰岛语",     // is
+		20:  "拉脱维亚语",   // lv
+		21:  "立陶宛语",    // lt
+		22:  "罗马尼亚语",   // ro
+		23:  "匈牙利语",    // hu
+		24:  "爱沙尼亚语",   // et
+		27:  "保加利亚语",   // bg
+		28:  "克罗地亚语",   // hr
+		29:  "塞尔维亚语",   // sr
+		30:  "爱尔兰语",    // ga
+		31:  "加利西亚语",   // gl
+		32:  "他加禄语",    // tl
+		33:  "土耳其语",    // tr
+		34:  "乌克兰语",    // uk
+		35:  "印地语",     // hi
+		36:  "马其顿语",    // mk
+		37:  "孟加拉语",    // bn
+		38:  "印度尼西亚语",  // id
+		39:  "拉丁语",     // la
+		40:  "马来语",     // ms
+		42:  "威尔士语",    // cy
+		45:  "阿尔巴尼亚语",  // sq
+		46:  "泰米尔语",    // ta
+		47:  "白俄罗斯语",   // be
+		50:  "乌尔都语",    // ur
+		53:  "泰语",      // th
+		54:  "阿拉伯语",    // ar
+		55:  "加泰罗尼亚语",  // ca
+		56:  "世界语",     // eo
+		57:  "巴斯克语",    // eu
+		62:  "斯瓦希里语",   // sw
+		63:  "斯洛文尼亚语",  // sl
+		66:  "越南语",     // vi
+		68:  "斯洛伐克语",   // sk
+		69:  "繁体中文",    // zh-Hant
+		74:  "阿塞拜疆语",   // az
+		75:  "格鲁吉亚语",   // ka
+		77:  "波斯语",     // fa
+		78:  "波斯尼亚语",   // bs
+		80:  "挪威尼诺斯克语", // nn
+		97:  "亚美尼亚语",   // hy
+		101: "南非荷兰语",   // af
+		114: "哈萨克语",    // kk
+	},
+}
+
+// Codes CLD2 accepts for each language, such as "he" and "zh-TW", from kCodeToLanguage.
+var codeLookup = map[string]Language{
+	"aa":         131, // aa
+	"ab":         130, // ab
+	"af":         101, // af
+	"ak":         161, // ak
+	"am":         73,  // am
+	"ar":         54,  // ar
+	"as":         111, // as
+	"ay":         132, // ay
+	"az":         74,  // az
+	"ba":         133, // ba
+	"be":         47,  // be
+	"bg":         27,  // bg
+	"bh":         51,  // bh
+	"bi":         134, // bi
+	"bn":         37,  // bn
+	"bo":         105, // bo
+	"br":         89,  // br
+	"bs":         78,  // bs
+	"ca":         55,  // ca
+	"ceb":        165, // ceb
+	"chr":        107, // chr
+	"co":         112, // co
+	"crs":        179, // crs
+	"cs":         17,  // cs
+	"cy":         42,  // cy
+	"da":         1,   // da
+	"de":         5,   // de
+	"dv":         106, // dv
+	"dz":         135, // dz
+	"ee":         166, // ee
+	"el":         18,  // el
+	"en":         0,   // en
+	"eo":         56,  // eo
+	"es":         14,  // es
+	"et":         24,  // et
+	"eu":         57,  // eu
+	"fa":         77,  // fa
+	"fi":         3,   // fi
+	"fj":         136, // fj
+	"fo":         70,  // fo
+	"fr":         4,   // fr
+	"fy":         67,  // fy
+	"ga":         30,  // ga
+	"gaa":        167, // gaa
+	"gd":         61,  // gd
+	"gl":         31,  // gl
+	"gn":         85,  // gn
+	"gu":         52,  // gu
+	"gv":         159, // gv
+	"ha":         138, // ha
+	"haw":        164, // haw
+	"he":         6,   // iw
+	"hi":         35,  // hi
+	"hmn":        168, // hmn
+	"hr":         28,  // hr
+	"ht":         139, // ht
+	"hu":         23,  // hu
+	"hy":         97,  // hy
+	"ia":         58,  // ia
+	"id":         38,  // id
+	"ie":         113, // ie
+	"ig":         162, // ig
+	"ik":         140, // ik
+	"is":         19,  // is
+	"it":         7,   // it
+	"iu":         141, // iu
+	"iw":         6,   // iw
+	"ja":         8,   // ja
+	"jv":         48,  // jw
+	"jw":         48,  // jw
+	"ka":         75,  // ka
+	"kha":        156, // kha
+	"kk":         114, // kk
+	"kl":         137, // kl
+	"km":         104, // km
+	"kn":         59,  // kn
+	"ko":         9,   // ko
+	"kri":        169, // kri
+	"ks":         142, // ks
+	"ku":         95,  // ku
+	"ky":         88,  // ky
+	"la":         39,  // la
+	"lb":         102, // lb
+	"lg":         158, // lg
+	"lif":        109, // lif
+	"ln":         115, // ln
+	"lo":         98,  // lo
+	"loz":        170, // loz
+	"lt":         21,  // lt
+	"lua":        171, // lua
+	"luo":        172, // luo
+	"lv":         20,  // lv
+	"mfe":        163, // mfe
+	"mg":         144, // mg
+	"mi":         128, // mi
+	"mk":         36,  // mk
+	"ml":         41,  // ml
+	"mn":         96,  // mn
+	"mo":         22,  // ro
+	"mr":         64,  // mr
+	"ms":         40,  // ms
+	"mt":         65,  // mt
+	"my":         103, // my
+	"na":         145, // na
+	"nb":         10,  // no
+	"ne":         43,  // ne
+	"new":        173, // new
+	"nl":         2,   // nl
+	"nn":         80,  // nn
+	"no":         10,  // no
+	"nr":         506, // nr
+	"nso":        177, // nso
+	"ny":         174, // ny
+	"oc":         49,  // oc
+	"om":         146, // om
+	"or":         110, // or
+	"os":         175, // os
+	"pa":         60,  // pa
+	"pam":        176, // pam
+	"pl":         11,  // pl
+	"ps":         117, // ps
+	"pt":         12,  // pt
+	"qu":         118, // qu
+	"raj":        178, // raj
+	"rm":         100, // rm
+	"rn":         147, // rn
+	"ro":         22,  // ro
+	"ru":         13,  // ru
+	"rw":         143, // rw
+	"sa":         150, // sa
+	"sco":        157, // sco
+	"sd":         99,  // sd
+	"sg":         149, // sg
+	"sh-Cyrl":    29,  // sr
+	"sh-Latn":    28,  // hr
+	"si":         79,  // si
+	"sit-Limb":   109, // lif
+	"sit-NP":     109, // lif
+	"sk":         68,  // sk
+	"sl":         63,  // sl
+	"sm":         148, // sm
+	"sn":         119, // sn
+	"so":         93,  // so
+	"sq":         45,  // sq
+	"sr":         29,  // sr
+	"sr-Latn-ME": 160, // sr-ME
+	"sr-ME":      160, // sr-ME
+	"srM":        160, // sr-ME
+	"srm":        160, // sr-ME
+	"ss":         151, // ss
+	"st":         86,  // st
+	"su":         71,  // su
+	"sv":         15,  // sv
+	"sw":         62,  // sw
+	"syr":        108, // syr
+	"ta":         46,  // ta
+	"te":         44,  // te
+	"tg":         120, // tg
+	"th":         53,  // th
+	"ti":         76,  // ti
+	"tk":         87,  // tk
+	"tl":         32,  // tl
+	"tlh":        510, // tlh
+	"tn":         153, // tn
+	"to":         122, // to
+	"tr":         33,  // tr
+	"ts":         152, // ts
+	"tt":         121, // tt
+	"tum":        180, // tum
+	"tw":         90,  // tw
+	"ug":         94,  // ug
+	"uk":         34,  // uk
+	"un":         26,  // un
+	"ur":         50,  // ur
+	"uz":         72,  // uz
+	"ve":         181, // ve
+	"vi":         66,  // vi
+	"vo":         154, // vo
+	"war":        182, // war
+	"wo":         129, // wo
+	"xh":         83,  // xh
+	"xx-Arab":    518, // xx-Arab
+	"xx-Armi":    598, // xx-Armi
+	"xx-Armn":    516, // xx-Armn
+	"xx-Avst":    591, // xx-Avst
+	"xx-Bali":    573, // xx-Bali
+	"xx-Bamu":    595, // xx-Bamu
+	"xx-Batk":    604, // xx-Batk
+	"xx-Beng":    522, // xx-Beng
+	"xx-Bopo":    546, // xx-Bopo
+	"xx-Brah":    605, // xx-Brah
+	"xx-Brai":    564, // xx-Brai
+	"xx-Bugi":    565, // xx-Bugi
+	"xx-Buhd":    555, // xx-Buhd
+	"xx-Cakm":    607, // xx-Cakm
+	"xx-Cans":    539, // xx-Cans
+	"xx-Cari":    586, // xx-Cari
+	"xx-Cham":    588, // xx-Cham
+	"xx-Cher":    538, // xx-Cher
+	"xx-Copt":    566, // xx-Copt
+	"xx-Cprt":    563, // xx-Cprt
+	"xx-Cyrl":    515, // xx-Cyrl
+	"xx-Deva":    521, // xx-Deva
+	"xx-Dsrt":    551, // xx-Dsrt
+	"xx-Egyp":    592, // xx-Egyp
+	"xx-Ethi":    537, // xx-Ethi
+	"xx-Geor":    535, // xx-Geor
+	"xx-Glag":    568, // xx-Glag
+	"xx-Goth":    550, // xx-Goth
+	"xx-Grek":    514, // xx-Grek
+	"xx-Gujr":    524, // xx-Gujr
+	"xx-Guru":    523, // xx-Guru
+	"xx-Hang":    536, // xx-Hang
+	"xx-Hani":    547, // xx-Hani
+	"xx-Hano":    554, // xx-Hano
+	"xx-Hebr":    517, // xx-Hebr
+	"xx-Hira":    544, // xx-Hira
+	"xx-Ital":    549, // xx-Ital
+	"xx-Java":    596, // xx-Java
+	"xx-Kali":    583, // xx-Kali
+	"xx-Kana":    545, // xx-Kana
+	"xx-Khar":    572, // xx-Khar
+	"xx-Khmr":    542, // xx-Khmr
+	"xx-Knda":    528, // xx-Knda
+	"xx-Kthi":    603, // xx-Kthi
+	"xx-Lana":    589, // xx-Lana
+	"xx-Laoo":    532, // xx-Laoo
+	"xx-Latn":    513, // xx-Latn
+	"xx-Lepc":    579, // xx-Lepc
+	"xx-Limb":    557, // xx-Limb
+	"xx-Linb":    559, // xx-Linb
+	"xx-Lisu":    594, // xx-Lisu
+	"xx-Lyci":    585, // xx-Lyci
+	"xx-Lydi":    587, // xx-Lydi
+	"xx-Mand":    606, // xx-Mand
+	"xx-Merc":    608, // xx-Merc
+	"xx-Mero":    609, // xx-Mero
+	"xx-Mlym":    529, // xx-Mlym
+	"xx-Mong":    543, // xx-Mong
+	"xx-Mtei":    597, // xx-Mtei
+	"xx-Mymr":    534, // xx-Mymr
+	"xx-Nkoo":    577, // xx-Nkoo
+	"xx-Ogam":    540, // xx-Ogam
+	"xx-Olck":    580, // xx-Olck
+	"xx-Orkh":    602, // xx-Orkh
+	"xx-Orya":    525, // xx-Orya
+	"xx-Osma":    562, // xx-Osma
+	"xx-Phag":    576, // xx-Phag
+	"xx-Phli":    601, // xx-Phli
+	"xx-Phnx":    575, // xx-Phnx
+	"xx-Plrd":    610, // xx-Plrd
+	"xx-Prti":    600, // xx-Prti
+	"xx-Qaai":    552, // xx-Qaai
+	"xx-Rjng":    584, // xx-Rjng
+	"xx-Runr":    541, // xx-Runr
+	"xx-Samr":    593, // xx-Samr
+	"xx-Sarb":    599, // xx-Sarb
+	"xx-Saur":    582, // xx-Saur
+	"xx-Shaw":    561, // xx-Shaw
+	"xx-Shrd":    611, // xx-Shrd
+	"xx-Sinh":    530, // xx-Sinh
+	"xx-Sora":    612, // xx-Sora
+	"xx-Sund":    578, // xx-Sund
+	"xx-Sylo":    570, // xx-Sylo
+	"xx-Syrc":    519, // xx-Syrc
+	"xx-Tagb":    556, // xx-Tagb
+	"xx-Takr":    613, // xx-Takr
+	"xx-Tale":    558, // xx-Tale
+	"xx-Talu":    567, // xx-Talu
+	"xx-Taml":    526, // xx-Taml
+	"xx-Tavt":    590, // xx-Tavt
+	"xx-Telu":    527, // xx-Telu
+	"xx-Tfng":    569, // xx-Tfng
+	"xx-Tglg":    553, // xx-Tglg
+	"xx-Thaa":    520, // xx-Thaa
+	"xx-Thai":    531, // xx-Thai
+	"xx-Tibt":    533, // xx-Tibt
+	"xx-Ugar":    560, // xx-Ugar
+	"xx-Vaii":    581, // xx-Vaii
+	"xx-Xpeo":    571, // xx-Xpeo
+	"xx-Xsux":    574, // xx-Xsux
+	"xx-Yiii":    548, // xx-Yiii
+	"xx-Zyyy":    512, // xx-Zyyy
+	"xxx":        25,  // xxx
+	"yi":         91,  // yi
+	"yo":         123, // yo
+	"za":         155, // za
+	"zh":         16,  // zh
+	"zh-CN":      16,  // zh
+	"zh-HK":      69,  // zh-Hant
+	"zh-Hani":    16,  // zh
+	"zh-Hans":    16,  // zh
+	"zh-Hant":    69,  // zh-Hant
+	"zh-SG":      69,  // zh-Hant
+	"zh-TW":      69,  // zh-Hant
+	"zhT":        69,  // zh-Hant
+	"zht":        69,  // zh-Hant
+	"zu":         84,  // zu
+	"zzb":        507, // zzb
+	"zze":        511, // zze
+	"zzh":        509, // zzh
+	"zzp":        508, // zzp
+}
@@ -0,0 +1,65 @@
+package cld2
+
+import "testing"
+
+func TestLanguageString(t *testing.T) {
+	for _, tc := range []struct {
+		lang Language
+		want string
+	}{
+		{GERMAN, "German"},
+		{CHINESE, "Chinese"},
+		{CHINESE_T, "Chinese (Traditional)"},
+		{SCOTS_GAELIC, "Scottish Gaelic"},
+		{LUO_KENYA_AND_TANZANIA, "Luo"},
+		{X_Lycian, "Lycian script"},
+		{X_PIG_LATIN, "Pig Latin"},
+		{UNKNOWN_LANGUAGE, "Unknown"},
+		{Language(81), "Language(81)"},
+		{NUM_LANGUAGES, "Language(614)"},
+	} {
+		if got := tc.lang.String(); got != tc.want {
+			t.Errorf("want %q, got %q", tc.want, got)
+		}
+	}
+}
+
+func TestNativeName(t *testing.T) {
+	if got := GERMAN.NativeName(); got != "Deutsch" {
+		t.Errorf("want 'Deutsch', got %q", got)
+	}
+	if got := JAPANESE.NativeName(); got != "日本語" {
+		t.Errorf("want '日本語', got %q", got)
+	}
+	if got := X_Lycian.NativeName(); got != "Lycian script" {
+		t.Errorf("want English name fallback, got %q", got)
+	}
+}
+
+func TestDisplayName(t *testing.T) {
+	for _, tc := range []struct {
+		lang, in Language
+		want     string
+	}{
+		{GERMAN, FRENCH, "allemand"},
+		{GERMAN, GERMAN, "Deutsch"},
+		{GERMAN, ENGLISH, "German"},
+		{DANISH, JAPANESE, "デンマーク語"},
+		{SPANISH, CHINESE, "西班牙语"},
+		{HAWAIIAN, SPANISH, "Hawaiian"},
+		{GERMAN, HAWAIIAN, "German"},
+	} {
+		if got := tc.lang.DisplayName(tc.in); got != tc.want {
+			t.Errorf("%v in %v: want %q, got %q", tc.lang, tc.in, tc.want, got)
+		}
+	}
+
+	for _, ui := range DisplayLanguages() {
+		if ui.DisplayName(ui) != ui.NativeName() {
+			t.Errorf("want %v to name itself natively", ui)
+		}
+		if _, ok := displayNames[ui][ENGLISH]; !ok {
+			t.Errorf("want a name for English in %v", ui)
+		}
+	}
+}
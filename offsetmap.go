@@ -0,0 +1,231 @@
+package cld2
+
+import "sort"
+
+// OffsetMap maps byte offsets between a text A and a text A' derived
+// from it, such as the HTML of a page and the text extracted from it.
+// It is a port of OffsetMap in offsetmap.h: the map is built from a
+// series of Copy, Insert and Delete operations that describe how A' was
+// made from A, and all bytes past the last operation correspond one to
+// one. The zero value is the identity map.
+type OffsetMap struct {
+	ranges  []mapRange
+	maxA    int // end of the last range in A
+	maxPrim int // end of the last range in A'
+}
+
+type mapOp uint8
+
+const (
+	copyOp   mapOp = iota // bytes are the same in A and A'
+	insertOp              // bytes are only in A'
+	deleteOp              // bytes are only in A
+)
+
+// mapRange is one operation, starting at offset a in A and prim in A'.
+type mapRange struct {
+	op      mapOp
+	n       int
+	a, prim int
+}
+
+func (r mapRange) endA() int {
+	if r.op == insertOp {
+		return r.a
+	}
+	return r.a + r.n
+}
+
+func (r mapRange) endPrim() int {
+	if r.op == deleteOp {
+		return r.prim
+	}
+	return r.prim + r.n
+}
+
+// Copy adds n bytes that correspond in A and A'.
+func (m *OffsetMap) Copy(n int) {
+	m.add(copyOp, n)
+}
+
+// Insert adds n bytes to A' that are not in A.
+// As in CLD2, Delete(1) followed by Insert(1) is the same as Copy(1).
+func (m *OffsetMap) Insert(n int) {
+	if n == 1 && m.lastIs(deleteOp, 1) {
+		m.replaceLast()
+		return
+	}
+	m.add(insertOp, n)
+}
+
+// Delete adds n bytes of A that are not in A'.
+// As in CLD2, Insert(1) followed by Delete(1) is the same as Copy(1).
+func (m *OffsetMap) Delete(n int) {
+	if n == 1 && m.lastIs(insertOp, 1) {
+		m.replaceLast()
+		return
+	}
+	m.add(deleteOp, n)
+}
+
+func (m *OffsetMap) lastIs(op mapOp, n int) bool {
+	k := len(m.ranges)
+	return k > 0 && m.ranges[k-1].op == op && m.ranges[k-1].n == n
+}
+
+// replaceLast turns a last insert or delete of one byte into a copy.
+func (m *OffsetMap) replaceLast() {
+	last := m.ranges[len(m.ranges)-1]
+	m.ranges = m.ranges[:len(m.ranges)-1]
+	m.maxA, m.maxPrim = last.a, last.prim
+	m.add(copyOp, 1)
+}
+
+// add appends an operation, merging it with the last one if possible.
+func (m *OffsetMap) add(op mapOp, n int) {
+	if n <= 0 {
+		return
+	}
+	if k := len(m.ranges); k > 0 && m.ranges[k-1].op == op {
+		m.ranges[k-1].n += n
+	} else {
+		m.ranges = append(m.ranges, mapRange{op: op, n: n, a: m.maxA, prim: m.maxPrim})
+	}
+	if op != insertOp {
+		m.maxA += n
+	}
+	if op != deleteOp {
+		m.maxPrim += n
+	}
+}
+
+// MapBack returns the offset in A that corresponds to offset prim in A'.
+// Inserted bytes map to the offset in A where they were inserted.
+func (m OffsetMap) MapBack(prim int) int {
+	if prim < 0 {
+		return 0
+	}
+	if prim >= m.maxPrim {
+		return prim - m.maxPrim + m.maxA
+	}
+	// Find the first range ending after prim in A'. That is never
+	// a delete, which is empty in A'.
+	i := sort.Search(len(m.ranges), func(i int) bool {
+		return m.ranges[i].endPrim() > prim
+	})
+	r := m.ranges[i]
+	if r.op == insertOp {
+		return r.a
+	}
+	return r.a + prim - r.prim
+}
+
+// MapForward returns the offset in A' that corresponds to offset a in A.
+// Deleted bytes map to the offset in A' where they were deleted.
+func (m OffsetMap) MapForward(a int) int {
+	if a < 0 {
+		return 0
+	}
+	if a >= m.maxA {
+		return a - m.maxA + m.maxPrim
+	}
+	// Find the first range ending after a in A. That is never
+	// an insert, which is empty in A.
+	i := sort.Search(len(m.ranges), func(i int) bool {
+		return m.ranges[i].endA() > a
+	})
+	r := m.ranges[i]
+	if r.op == deleteOp {
+		return r.prim
+	}
+	return r.prim + a - r.a
+}
+
+// ComposeOffsetMap returns the map h from A to a text A2 given the
+// map f from A to A' and the map g from A' to A2.
+//
+// h maps offsets as f and then g do, except where f deletes and g
+// inserts at the same place. There h replaces the deleted bytes with
+// the inserted ones, as CLD2 does, so both MapForward and MapBack can
+// differ from mapping through f and g in turn.
+func ComposeOffsetMap(g, f OffsetMap) OffsetMap {
+	var h OffsetMap
+	fi, gi := opCursor{ranges: f.ranges}, opCursor{ranges: g.ranges}
+	for !fi.done() || !gi.done() {
+		fop, fn := fi.peek()
+		gop, gn := gi.peek()
+		switch {
+		case gop == insertOp:
+			h.Insert(gn)
+			gi.next(gn)
+		case fop == deleteOp:
+			h.Delete(fn)
+			fi.next(fn)
+		default:
+			// Both consume A'. Past its end, either map is a copy.
+			n := fn
+			if fi.done() || !gi.done() && gn < n {
+				n = gn
+			}
+			switch {
+			case fop == copyOp && gop == copyOp:
+				h.Copy(n)
+			case fop == copyOp:
+				h.Delete(n)
+			case gop == copyOp:
+				h.Insert(n)
+			}
+			fi.next(n)
+			gi.next(n)
+		}
+	}
+	return h
+}
+
+// opCursor steps through the operations of a map.
+type opCursor struct {
+	ranges []mapRange
+	used   int // bytes of ranges[0] already consumed
+}
+
+func (c *opCursor) done() bool {
+	return len(c.ranges) == 0
+}
+
+// peek returns the current operation and how much of it is left,
+// or a copy if the map is done.
+func (c *opCursor) peek() (mapOp, int) {
+	if c.done() {
+		return copyOp, 0
+	}
+	return c.ranges[0].op, c.ranges[0].n - c.used
+}
+
+func (c *opCursor) next(n int) {
+	if c.done() {
+		return
+	}
+	c.used += n
+	if c.used >= c.ranges[0].n {
+		c.ranges, c.used = c.ranges[1:], 0
+	}
+}
+
+// offsetMapFromPositions returns the map from a text A' to A given the
+// offset in A of each byte of A', followed by the offset of its end.
+func offsetMapFromPositions(pos []int) OffsetMap {
+	var m OffsetMap
+	if len(pos) == 0 {
+		return m
+	}
+	m.add(deleteOp, pos[0])
+	for i := 0; i+1 < len(pos); i++ {
+		if d := pos[i+1] - pos[i]; d > 0 {
+			m.add(copyOp, 1)
+			m.add(deleteOp, d-1)
+		} else {
+			m.add(insertOp, 1)
+		}
+	}
+	return m
+}
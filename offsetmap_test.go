@@ -0,0 +1,114 @@
+package cld2
+
+import "testing"
+
+func TestOffsetMapBack(t *testing.T) {
+	// A  = "ab<i>cd&amp;e", A' = " abcd&e"
+	var m OffsetMap
+	m.Insert(1)
+	m.Copy(2)
+	m.Delete(3)
+	m.Copy(2)
+	m.Copy(1)
+	m.Delete(4)
+	m.Copy(1)
+	for prim, want := range []int{0, 0, 1, 5, 6, 7, 12, 13, 14} {
+		if got := m.MapBack(prim); got != want {
+			t.Errorf("MapBack(%d): want %d, got %d", prim, want, got)
+		}
+	}
+	if got := m.MapBack(-1); got != 0 {
+		t.Errorf("MapBack(-1): want 0, got %d", got)
+	}
+
+	// Delete(1) Insert(1) is a copy, as in CLD2.
+	m = OffsetMap{}
+	m.Copy(2)
+	m.Delete(1)
+	m.Insert(1)
+	m.Insert(2)
+	for prim, want := range []int{0, 1, 2, 3, 3, 3} {
+		if got := m.MapBack(prim); got != want {
+			t.Errorf("MapBack(%d) after Delete(1) Insert(1): want %d, got %d", prim, want, got)
+		}
+	}
+
+	if got := (OffsetMap{}).MapBack(7); got != 7 {
+		t.Errorf("want identity for zero map, got %d", got)
+	}
+}
+
+func TestOffsetMapFromPositions(t *testing.T) {
+	// From ExtractText("<p>Caf&eacute; x</p>"): " \nCafé x "
+	pos := []int{0, 0, 3, 4, 5, 6, 7, 14, 15, 16, 20}
+	m := offsetMapFromPositions(pos)
+	for prim, want := range pos {
+		if got := m.MapBack(prim); got != want {
+			t.Errorf("MapBack(%d): want %d, got %d", prim, want, got)
+		}
+	}
+}
+
+func TestOffsetMapForward(t *testing.T) {
+	// A  = "ab<i>cd&amp;e", A' = " abcd&e"
+	var m OffsetMap
+	m.Insert(1)
+	m.Copy(2)
+	m.Delete(3)
+	m.Copy(3)
+	m.Delete(4)
+	m.Copy(1)
+	for a, want := range []int{1, 2, 3, 3, 3, 3, 4, 5, 6, 6, 6, 6, 6, 7, 8} {
+		if got := m.MapForward(a); got != want {
+			t.Errorf("MapForward(%d): want %d, got %d", a, want, got)
+		}
+	}
+}
+
+func TestComposeOffsetMap(t *testing.T) {
+	// f removes a tag, g lowercases "ẞ" (3 bytes) to "ß" (2 bytes)
+	// and inserts a space at the front.
+	var f, g OffsetMap
+	f.Copy(2)
+	f.Delete(3)
+	f.Copy(5)
+	g.Insert(1)
+	g.Copy(4)
+	g.Copy(2)
+	g.Delete(1)
+	g.Copy(3)
+	h := ComposeOffsetMap(g, f)
+	for a := 0; a <= 12; a++ {
+		if want, got := g.MapForward(f.MapForward(a)), h.MapForward(a); got != want {
+			t.Errorf("MapForward(%d): want %d, got %d", a, want, got)
+		}
+	}
+	for a2 := 0; a2 <= 10; a2++ {
+		if want, got := f.MapBack(g.MapBack(a2)), h.MapBack(a2); got != want {
+			t.Errorf("MapBack(%d): want %d, got %d", a2, want, got)
+		}
+	}
+
+	// Deleting in f and inserting in g at the same place is a copy.
+	f, g = OffsetMap{}, OffsetMap{}
+	f.Copy(2)
+	f.Delete(1)
+	f.Copy(1)
+	g.Copy(2)
+	g.Insert(1)
+	g.Copy(1)
+	h = ComposeOffsetMap(g, f)
+	for a := 0; a <= 5; a++ {
+		if got := h.MapForward(a); got != a {
+			t.Errorf("MapForward(%d): want identity, got %d", a, got)
+		}
+		if got := h.MapBack(a); got != a {
+			t.Errorf("MapBack(%d): want identity, got %d", a, got)
+		}
+	}
+	// Mapping through f and g in turn moves the deleted byte past the
+	// inserted one.
+	if got := g.MapForward(f.MapForward(2)); got != 3 {
+		t.Errorf("g.MapForward(f.MapForward(2)): want 3, got %d", got)
+	}
+}
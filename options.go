@@ -0,0 +1,24 @@
+package cld2
+
+// Options control how DetectWithOptions treats its input.
+// The zero value detects plain UTF-8 text without hints,
+// replacing ill-formed UTF-8 with U+FFFD.
+type Options struct {
+	HTML  bool     // text is HTML: skip tags, scripts and styles and expand entities
+	Hints *Hints   // what is known about the text from elsewhere, or nil
+	UTF8  UTF8Mode // what to do with text that isn't valid UTF-8
+
+	Spans   bool        // also return the language of each span of the text
+	Offsets Coordinates // what span offsets refer to
+
+	CharOffsets bool // also give span offsets in runes and UTF-16 code units
+
+	Calibration *Calibration       // for Estimate.Confidence, or nil for none
+	Reliability *ReliabilityPolicy // decides Languages.Reliable, or nil for CLD2's rule
+
+	CloseSets bool // label estimates with their close set and merge them by it
+
+	WithoutHints bool // also detect without hints, into Languages.WithoutHints
+
+	noLangTags bool // ignore lang attributes in HTML, for WithoutHints
+}
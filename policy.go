@@ -0,0 +1,66 @@
+package cld2
+
+// ReliabilityCriterion names the test a result failed to be reliable.
+type ReliabilityCriterion string
+
+const (
+	CriterionCLD2        ReliabilityCriterion = "cld2"        // CLD2's own rule
+	CriterionNoLanguage  ReliabilityCriterion = "no-language" // no language was found
+	CriterionTextBytes   ReliabilityCriterion = "text-bytes"  // too few letters
+	CriterionPercent     ReliabilityCriterion = "percent"     // top language has too small a share
+	CriterionMargin      ReliabilityCriterion = "margin"      // runner-up too close to the top language
+	CriterionReliability ReliabilityCriterion = "reliability" // CLD2's reliability of the top language too low
+	CriterionScoreLow    ReliabilityCriterion = "score-low"   // text scores too far below its language
+	CriterionScoreHigh   ReliabilityCriterion = "score-high"  // text scores too far above its language
+)
+
+// ReliabilityPolicy re-judges whether a result is reliable, after CLD2
+// has detected it, in place of CLD2's final verdict. Search indexing may
+// want a lenient policy and automatic translation a strict one. Zero
+// fields are not checked; all the checks are on the top estimate. CLD2's
+// own pruning of unreliable chunks and languages during detection still
+// happens first and isn't changed by the policy.
+type ReliabilityPolicy struct {
+	MinTextBytes   int // letter bytes scored
+	MinPercent     int // Percent of the top language
+	MinMargin      int // Percent of the top language over the runner-up
+	MinReliability int // CLD2's reliability 0..100 of the top language; CLD2 keeps languages at 41
+
+	// NormScore over the average score of real text in the top language.
+	// Real text is close to 1; CLD2 trusts scores within a factor of 1.5.
+	MinScoreRatio float64
+	MaxScoreRatio float64
+
+	RequireCLD2 bool // also require CLD2's own rule to pass
+}
+
+// check returns the first criterion res fails, or "" if it passes.
+// fs are the features of the estimates of res and
+// cld2Reliable is CLD2's own verdict.
+func (p *ReliabilityPolicy) check(res Languages, fs []calibrationFeatures, cld2Reliable bool) ReliabilityCriterion {
+	if len(res.Estimates) == 0 {
+		return CriterionNoLanguage
+	}
+	top, f := res.Estimates[0], fs[0]
+	second := 0
+	if len(res.Estimates) > 1 {
+		second = res.Estimates[1].Percent
+	}
+	switch {
+	case res.TextBytes < p.MinTextBytes:
+		return CriterionTextBytes
+	case top.Percent < p.MinPercent:
+		return CriterionPercent
+	case top.Percent-second < p.MinMargin:
+		return CriterionMargin
+	case f.reliability < p.MinReliability:
+		return CriterionReliability
+	case p.MinScoreRatio > 0 && f.ratio < p.MinScoreRatio:
+		return CriterionScoreLow
+	case p.MaxScoreRatio > 0 && f.ratio > p.MaxScoreRatio:
+		return CriterionScoreHigh
+	case p.RequireCLD2 && !cld2Reliable:
+		return CriterionCLD2
+	}
+	return ""
+}
@@ -0,0 +1,41 @@
+package cld2
+
+import "testing"
+
+func TestReliabilityPolicy(t *testing.T) {
+	res := Languages{
+		Estimates: []Estimate{
+			{Language: NORWEGIAN, Percent: 60, NormScore: 600},
+			{Language: DANISH, Percent: 35, NormScore: 500},
+		},
+		TextBytes: 100,
+	}
+	fs := estimateFeatures(res, []int{50, 30}, []int{1000, 1000}, 0)
+	for _, tc := range []struct {
+		policy ReliabilityPolicy
+		cld2   bool
+		want   ReliabilityCriterion
+	}{
+		{ReliabilityPolicy{}, false, ""},
+		{ReliabilityPolicy{RequireCLD2: true}, false, CriterionCLD2},
+		{ReliabilityPolicy{RequireCLD2: true}, true, ""},
+		{ReliabilityPolicy{MinTextBytes: 100, MinPercent: 60, MinMargin: 25, MinReliability: 50}, false, ""},
+		{ReliabilityPolicy{MinTextBytes: 101}, true, CriterionTextBytes},
+		{ReliabilityPolicy{MinPercent: 61}, true, CriterionPercent},
+		{ReliabilityPolicy{MinMargin: 26}, true, CriterionMargin},
+		{ReliabilityPolicy{MinReliability: 51}, true, CriterionReliability},
+		{ReliabilityPolicy{MinScoreRatio: 0.5, MaxScoreRatio: 1.5}, true, ""},
+		{ReliabilityPolicy{MinScoreRatio: 0.7}, true, CriterionScoreLow},
+		{ReliabilityPolicy{MaxScoreRatio: 0.5}, true, CriterionScoreHigh},
+		// The first failing criterion is reported.
+		{ReliabilityPolicy{MinPercent: 90, MinMargin: 90, RequireCLD2: true}, false, CriterionPercent},
+	} {
+		if got := tc.policy.check(res, fs, tc.cld2); got != tc.want {
+			t.Errorf("%+v (cld2 %v): want %q, got %q", tc.policy, tc.cld2, tc.want, got)
+		}
+	}
+
+	if got := new(ReliabilityPolicy).check(Languages{}, nil, true); got != CriterionNoLanguage {
+		t.Errorf("want %q without estimates, got %q", CriterionNoLanguage, got)
+	}
+}
@@ -0,0 +1,181 @@
+package cld2
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// The range of prior weights, those of CLD2's OneCLDLangPrior.
+const (
+	MinPriorWeight = -32
+	MaxPriorWeight = 31
+)
+
+// maxPackedPriors is kMaxOneCLDLangPrior, the most priors CLD2 collects.
+const maxPackedPriors = 14
+
+// Prior is the weight of one language before looking at the text.
+type Prior struct {
+	Language Language
+	Weight   int
+}
+
+// Priors collect weighted language priors from any source, such as a
+// user's language history or statistics of a site, to steer detection
+// through Hints.Priors. The zero value has no priors.
+//
+// Each step of weight makes a language about three times as likely.
+// CLD2's own hints use 4 for an encoding and 8 for a known language, and
+// boosts above 12 count as 12. A negative weight rules a language out,
+// even one other hints boost.
+// CLD2 keeps the four priors with the largest absolute weights of all
+// hints together, as TrimCLDLangPriors does.
+type Priors struct {
+	weights map[Language]int
+}
+
+// Add adds weight to the prior of lang and returns p, so calls can be
+// chained. The sum is kept within MinPriorWeight..MaxPriorWeight.
+// Unknown and invalid languages are ignored.
+func (p *Priors) Add(lang Language, weight int) *Priors {
+	if lang == UNKNOWN_LANGUAGE || lang >= NUM_LANGUAGES {
+		return p
+	}
+	if p.weights == nil {
+		p.weights = make(map[Language]int)
+	}
+	w := p.weights[lang] + weight
+	if w < MinPriorWeight {
+		w = MinPriorWeight
+	}
+	if w > MaxPriorWeight {
+		w = MaxPriorWeight
+	}
+	p.weights[lang] = w
+	return p
+}
+
+// List returns the priors with a nonzero weight, largest absolute
+// weight first.
+func (p *Priors) List() []Prior {
+	var list []Prior
+	for lang, w := range p.weights {
+		if w != 0 {
+			list = append(list, Prior{lang, w})
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		wi, wj := abs(list[i].Weight), abs(list[j].Weight)
+		if wi != wj {
+			return wi > wj
+		}
+		return list[i].Language < list[j].Language
+	})
+	return list
+}
+
+// packed returns the strongest priors packed as OneCLDLangPrior:
+// the language in the bottom 10 bits and the weight in the top 6.
+func (p *Priors) packed() []int16 {
+	list := p.List()
+	if len(list) > maxPackedPriors {
+		list = list[:maxPackedPriors]
+	}
+	out := make([]int16, len(list))
+	for i, pr := range list {
+		out[i] = int16(pr.Weight<<10 | int(pr.Language))
+	}
+	return out
+}
+
+// PriorSource names where a prior applied by CLD2 came from.
+type PriorSource string
+
+const (
+	SourceHTMLLang        PriorSource = "html-lang"        // lang attributes and language meta tags in HTML
+	SourceContentLanguage PriorSource = "content-language" // Hints.ContentLanguage
+	SourceTLD             PriorSource = "tld"              // Hints.TLD
+	SourceEncoding        PriorSource = "encoding"         // Hints.Encoding
+	SourceLanguage        PriorSource = "language"         // Hints.Language
+	SourceExplicit        PriorSource = "explicit"         // Hints.Priors
+)
+
+// sourceBits are the sources in the order of CLD2's kPriorSource bits.
+var sourceBits = [...]PriorSource{
+	SourceHTMLLang,
+	SourceContentLanguage,
+	SourceTLD,
+	SourceEncoding,
+	SourceLanguage,
+	SourceExplicit,
+}
+
+// priorSources returns the sources set in kPriorSource bits.
+func priorSources(bits int) []PriorSource {
+	var sources []PriorSource
+	for i, s := range sourceBits {
+		if bits&(1<<i) != 0 {
+			sources = append(sources, s)
+		}
+	}
+	return sources
+}
+
+// AppliedPrior is a prior CLD2 applied to detection, after merging all
+// hints and keeping the four strongest. Where hints agree on a language
+// the weight is their combination: TLD, encoding and language hints
+// add 2 to an earlier weight, the others take the largest.
+type AppliedPrior struct {
+	Language Language      `json:"code"`
+	Weight   int           `json:"weight"`
+	Sources  []PriorSource `json:"sources"`
+}
+
+// unpackPriors is the inverse of packed. A language that appears more
+// than once keeps its largest weight, as MergeCLDLangPriorsMax does.
+func unpackPriors(packed []int16) *Priors {
+	p := new(Priors)
+	for _, v := range packed {
+		lang, w := Language(v&0x3ff), int(v>>10)
+		if old, ok := p.weights[lang]; ok && old >= w {
+			continue
+		}
+		p.Add(lang, w-p.weights[lang])
+	}
+	return p
+}
+
+// DumpPriors formats priors as CLD2's DumpCLDLangPriors does, as
+// language code and weight pairs such as "fr.8 en.-4", strongest first.
+// It returns "" for nil.
+func DumpPriors(p *Priors) string {
+	if p == nil {
+		return ""
+	}
+	var b strings.Builder
+	for i, pr := range p.List() {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		fmt.Fprintf(&b, "%s.%d", pr.Language.Code(), pr.Weight)
+	}
+	return b.String()
+}
+
+// normalizeLangTags puts language tags in the form CLD2 looks up, as
+// GetLangTagsFromHtml does: lowercase, with underscores as hyphens and
+// commas between tags.
+func normalizeLangTags(tags string) string {
+	fields := strings.FieldsFunc(strings.ToLower(tags), func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+	return strings.ReplaceAll(strings.Join(fields, ","), "_", "-")
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
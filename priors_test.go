@@ -0,0 +1,97 @@
+package cld2
+
+import "testing"
+
+func TestPriors(t *testing.T) {
+	p := new(Priors).Add(GERMAN, 6).Add(ENGLISH, -4).Add(GERMAN, 30).Add(FRENCH, 2).Add(FRENCH, -2).Add(UNKNOWN_LANGUAGE, 8)
+	want := []Prior{{GERMAN, MaxPriorWeight}, {ENGLISH, -4}}
+	got := p.List()
+	if len(got) != len(want) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("want %v, got %v", want, got)
+		}
+	}
+	if got := new(Priors).Add(ITALIAN, -100).List(); len(got) != 1 || got[0].Weight != MinPriorWeight {
+		t.Errorf("want weight clamped to %d, got %v", MinPriorWeight, got)
+	}
+
+	for i, v := range p.packed() {
+		lang, w := Language(v&0x3ff), int(v>>10)
+		if lang != want[i].Language || w != want[i].Weight {
+			t.Errorf("packed %d: want %v, got %v %d", i, want[i], lang, w)
+		}
+	}
+
+	var many Priors
+	for lang := Language(1); lang < 30; lang++ {
+		many.Add(lang, int(lang))
+	}
+	if packed := many.packed(); len(packed) != maxPackedPriors || int(packed[0]>>10) != 29 {
+		t.Errorf("want the %d strongest priors, got %v", maxPackedPriors, packed)
+	}
+}
+
+func TestUnpackPriors(t *testing.T) {
+	p := new(Priors).Add(FRENCH, 8).Add(ENGLISH, -4).Add(GERMAN, 2)
+	got := unpackPriors(p.packed())
+	if a, b := DumpPriors(got), DumpPriors(p); a != b {
+		t.Errorf("want %q after round trip, got %q", b, a)
+	}
+	if s := DumpPriors(p); s != "fr.8 en.-4 de.2" {
+		t.Errorf("want fr.8 en.-4 de.2, got %q", s)
+	}
+	dup := []int16{int16(2<<10 | int(SPANISH)), int16(6<<10 | int(SPANISH)), int16(4<<10 | int(SPANISH))}
+	if s := DumpPriors(unpackPriors(dup)); s != "es.6" {
+		t.Errorf("want the largest weight es.6, got %q", s)
+	}
+	if s := DumpPriors(nil); s != "" {
+		t.Errorf("want empty dump for nil, got %q", s)
+	}
+}
+
+func TestNormalizeLangTags(t *testing.T) {
+	for in, want := range map[string]string{
+		"pt_BR":        "pt-br",
+		"en-US, fr":    "en-us,fr",
+		" de\tnl,,IT ": "de,nl,it",
+		"":             "",
+	} {
+		if got := normalizeLangTags(in); got != want {
+			t.Errorf("%q: want %q, got %q", in, want, got)
+		}
+	}
+}
+
+func TestPriorSources(t *testing.T) {
+	got := priorSources(1 | 4 | 32)
+	want := []PriorSource{SourceHTMLLang, SourceTLD, SourceExplicit}
+	if len(got) != len(want) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("want %v, got %v", want, got)
+		}
+	}
+	if got := priorSources(0); got != nil {
+		t.Errorf("want no sources, got %v", got)
+	}
+}
+
+func TestFlippedByHints(t *testing.T) {
+	res := Languages{Estimates: []Estimate{{Language: NORWEGIAN, Percent: 90}}}
+	if res.FlippedByHints() {
+		t.Error("want no flip without WithoutHints")
+	}
+	res.WithoutHints = &Languages{Estimates: []Estimate{{Language: NORWEGIAN, Percent: 70}}}
+	if res.FlippedByHints() {
+		t.Error("want no flip for the same top language")
+	}
+	res.WithoutHints.Estimates[0].Language = DANISH
+	if !res.FlippedByHints() {
+		t.Error("want flip for a different top language")
+	}
+}
@@ -0,0 +1,141 @@
+package cld2
+
+import (
+	"math"
+	"sort"
+	"unicode"
+)
+
+// QualityReason labels one way in which a text looks like junk.
+type QualityReason string
+
+const (
+	TooShort       QualityReason = "too-short"       // too few letters to judge
+	Repetitive     QualityReason = "repetitive"      // the same characters over and over
+	KeywordStuffed QualityReason = "keyword-stuffed" // runs of short or repeated words
+	RandomLetters  QualityReason = "random-letters"  // letters that don't score as any language
+)
+
+// TextQuality is the result of Quality.
+type TextQuality struct {
+	Score   float64         // 0 for junk to 1 for normal text
+	Reasons []QualityReason // what lowered the score, worst first
+}
+
+// minQualityBytes is the letter count below which a text is too short;
+// CLD2 treats spans under 32 bytes as short (kShortSpanThresh).
+const minQualityBytes = 32
+
+// spanReliability is how reliably CLD2 scored the text of a span, from
+// 0 to 100 by the margin of its language over the next one and by its
+// score against that of real text, weighted by bytes over its chunks.
+type spanReliability struct {
+	bytes        int
+	delta, score int
+}
+
+// assessQuality scores a detection result, the letters it was based on
+// and the reliability of each of its spans. Each heuristic gives a factor
+// from 0 to 1 and the score is their product.
+func assessQuality(res Languages, letters []byte, spans []spanReliability) TextQuality {
+	type factor struct {
+		reason QualityReason
+		value  float64
+	}
+	var factors []factor
+	add := func(reason QualityReason, value float64) {
+		factors = append(factors, factor{reason, math.Max(0, math.Min(1, value))})
+	}
+
+	add(TooShort, float64(res.TextBytes)/minQualityBytes)
+
+	// CLD2 squeezes chunks with 40% of bytes predicted; a text made
+	// of nothing else is worthless.
+	st := countSqueeze(letters)
+	if st.chunks > 0 {
+		add(Repetitive, 1-float64(st.predicted)/float64(st.chunks))
+	}
+	if st.chunks > 0 && st.words > 0 {
+		dense := float64(st.spaceDense) / float64(st.chunks)
+		rep := float64(st.repWords) / float64(st.words)
+		add(KeywordStuffed, 1-math.Max(dense, rep))
+	}
+
+	// Real text scores close to the average for its language and well
+	// ahead of other languages; gibberish does neither. As CLD2 does for
+	// each chunk, take the lower of the two.
+	reliable, bytes := 0, 0
+	for _, s := range spans {
+		r := s.delta
+		if s.score < r {
+			r = s.score
+		}
+		reliable += r * s.bytes
+		bytes += s.bytes
+	}
+	closeness := 0.0
+	if bytes > 0 {
+		closeness = float64(reliable) / float64(bytes) / 100
+	}
+	add(RandomLetters, closeness)
+
+	q := TextQuality{Score: 1}
+	for _, f := range factors {
+		q.Score *= f.value
+	}
+	sort.SliceStable(factors, func(i, j int) bool { return factors[i].value < factors[j].value })
+	for _, f := range factors {
+		if f.value < 0.75 {
+			q.Reasons = append(q.Reasons, f.reason)
+		}
+	}
+	return q
+}
+
+// reliabilityExpected is ReliabilityExpected from cldutil.cc as a
+// fraction: 1 if actual is within a factor of 1.5 of expected, falling
+// to 0 at a factor of 4. It is 1 if nothing is expected.
+func reliabilityExpected(actual, expected float64) float64 {
+	const ratio100, ratio0 = 1.5, 4.0
+	if expected == 0 {
+		return 1
+	}
+	if actual <= 0 {
+		return 0
+	}
+	ratio := math.Max(actual/expected, expected/actual)
+	if ratio <= ratio100 {
+		return 1
+	}
+	if ratio > ratio0 {
+		return 0
+	}
+	return (ratio0 - ratio) / (ratio0 - ratio100)
+}
+
+// scriptColumn returns the column of the expected score tables for the
+// letters, by their most frequent script: 0 for Latin, 1 for Cyrillic,
+// 2 for Arabic and 3 for anything else.
+func scriptColumn(letters []byte) int {
+	var counts [4]int
+	for _, r := range string(letters) {
+		switch {
+		case !unicode.IsLetter(r):
+		case unicode.Is(unicode.Latin, r):
+			counts[0]++
+		case unicode.Is(unicode.Cyrillic, r):
+			counts[1]++
+		case unicode.Is(unicode.Arabic, r):
+			counts[2]++
+		default:
+			counts[3]++
+		}
+	}
+	col := 0
+	for i, n := range counts {
+		if n > counts[col] {
+			col = i
+		}
+	}
+	return col
+}
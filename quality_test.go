@@ -0,0 +1,95 @@
+package cld2
+
+import (
+	"strings"
+	"testing"
+)
+
+const daLetters = " omkring personer har gennem de seneste år forladt eu landene for at deltage i krigen i irak og syrien" +
+	" det viser en ny rapport fra tænketanken international centre for counter terrorism som den hollandske regering har fået udarbejdet "
+
+func TestCountSqueeze(t *testing.T) {
+	st := countSqueeze([]byte(daLetters))
+	if st.predicted != 0 || st.spaceDense != 0 || st.repWords > 2 {
+		t.Errorf("want normal text not to be squeezed, got %+v", st)
+	}
+	st = countSqueeze([]byte(strings.Repeat("hahahaha", 40)))
+	if st.predicted < st.chunks-1 {
+		t.Errorf("want repeated text to be predicted, got %+v", st)
+	}
+	st = countSqueeze([]byte(strings.Repeat(" a b c d e f g", 10)))
+	if st.spaceDense != st.chunks {
+		t.Errorf("want single letters to be space-dense, got %+v", st)
+	}
+}
+
+func TestAssessQuality(t *testing.T) {
+	good := Languages{
+		Estimates: []Estimate{{Language: DANISH, Percent: 99, NormScore: 900}},
+		TextBytes: len(daLetters),
+		Reliable:  true,
+	}
+	q := assessQuality(good, []byte(daLetters), []spanReliability{{len(daLetters), 100, 100}})
+	if q.Score < 0.9 || len(q.Reasons) != 0 {
+		t.Errorf("want high quality for Danish text, got %+v", q)
+	}
+
+	spam := strings.Repeat(" buy cheap pills", 20)
+	res := Languages{
+		Estimates: []Estimate{{Language: ENGLISH, Percent: 99, NormScore: 1100}},
+		TextBytes: len(spam),
+		Reliable:  true,
+	}
+	q = assessQuality(res, []byte(spam), []spanReliability{{len(spam), 100, 90}})
+	if q.Score > 0.1 || !hasReason(q, Repetitive) || !hasReason(q, KeywordStuffed) {
+		t.Errorf("want repetitive keyword stuffing, got %+v", q)
+	}
+
+	random := " xqzvbn plmokijuh ytrewq azsxdc fvgbhn jmkilo pqowie urytla skdjfh gmznxb "
+	res = Languages{
+		Estimates: []Estimate{{Language: ENGLISH, Percent: 60, NormScore: 150}},
+		TextBytes: len(random),
+	}
+	// Most of it scored far below real English, and a little ahead of
+	// other languages only
+	q = assessQuality(res, []byte(random), []spanReliability{{60, 40, 0}, {len(random) - 60, 10, 30}})
+	if q.Score > 0.1 || q.Reasons[0] != RandomLetters {
+		t.Errorf("want random letters first, got %+v", q)
+	}
+
+	q = assessQuality(Languages{TextBytes: 8}, []byte(" hi there "), nil)
+	if q.Score != 0 || !hasReason(q, TooShort) {
+		t.Errorf("want too short, got %+v", q)
+	}
+}
+
+func hasReason(q TextQuality, reason QualityReason) bool {
+	for _, r := range q.Reasons {
+		if r == reason {
+			return true
+		}
+	}
+	return false
+}
+
+func TestReliabilityExpected(t *testing.T) {
+	for _, tc := range []struct{ actual, expected, want float64 }{
+		{1000, 1000, 1},
+		{700, 1000, 1},
+		{2000, 1000, 0.8},
+		{500, 1000, 0.8},
+		{5000, 1000, 0},
+		{0, 1000, 0},
+		{100, 0, 1},
+	} {
+		if got := reliabilityExpected(tc.actual, tc.expected); got < tc.want-1e-9 || got > tc.want+1e-9 {
+			t.Errorf("reliabilityExpected(%v, %v): want %v, got %v", tc.actual, tc.expected, tc.want, got)
+		}
+	}
+	if col := scriptColumn([]byte(" привет мир ")); col != 1 {
+		t.Errorf("want Cyrillic column 1, got %d", col)
+	}
+	if col := scriptColumn([]byte(" 日本語 ")); col != 3 {
+		t.Errorf("want other column 3, got %d", col)
+	}
+}
@@ -314,6 +314,23 @@ void SummaryBufferToDocTote(const SummaryBuffer* summarybuffer,
   }
 }
 
+// Add the reliability of each chunk of summarybuffer to vec, with offsets
+// mapped back to the original buffer as for ResultChunkVector
+void SummaryBufferToReliability(ScriptScanner* scanner,
+                                const SummaryBuffer* summarybuffer,
+                                std::vector<ChunkReliability>* vec) {
+  if (vec == NULL) {return;}
+  for (int i = 0; i < summarybuffer->n; ++i) {
+    const ChunkSummary* cs = &summarybuffer->chunksummary[i];
+    ChunkReliability cr;
+    cr.offset = scanner->MapBack(cs->offset);
+    cr.bytes = scanner->MapBack(cs->offset + cs->bytes) - cr.offset;
+    cr.reliability_delta = cs->reliability_delta;
+    cr.reliability_score = cs->reliability_score;
+    vec->push_back(cr);
+  }
+}
+
 // Turn on for debugging vectors
 static const bool kShowLettersOriginal = false;
 
@@ -1111,6 +1128,8 @@ void ProcessHitBuffer(const LangSpan& scriptspan,
   }
 
   SummaryBufferToDocTote(&summarybuffer, more_to_come, doc_tote);
+  SummaryBufferToReliability(scoringcontext->scanner, &summarybuffer,
+                             scoringcontext->chunkreliability);
   SummaryBufferToVector(scoringcontext->scanner, scriptspan.text,
                         &summarybuffer, more_to_come, vec);
 }
@@ -1152,6 +1171,15 @@ void ScoreEntireScriptSpan(const LangSpan& scriptspan,
                scoringcontext, NULL, &chunksummary);
   }
 
+  if (scoringcontext->chunkreliability != NULL) {
+    ChunkReliability cr;
+    cr.offset = scoringcontext->scanner->MapBack(1);
+    cr.bytes = scoringcontext->scanner->MapBack(bytes) - cr.offset;
+    cr.reliability_delta = reliability;
+    cr.reliability_score = reliability;
+    scoringcontext->chunkreliability->push_back(cr);
+  }
+
   // First byte is always a space
   JustOneItemToVector(scoringcontext->scanner, scriptspan.text,
                       one_one_lang, 1, bytes - 1, vec);
@@ -150,6 +150,8 @@ typedef struct {
                                       // distinct score to use
   const ScoringTables* scoringtables; // Probability lookup tables
   ScriptScanner* scanner;             // For ResultChunkVector backmap
+  std::vector<ChunkReliability>* chunkreliability;  // If not NULL, gets
+                                      // the reliability of each chunk
 
   // Inits boosts
   void init() {
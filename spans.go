@@ -0,0 +1,80 @@
+package cld2
+
+import "unicode/utf8"
+
+// Span is a run of the input in one language. Text that CLD2 found
+// too short or unreliable to tell is UNKNOWN_LANGUAGE.
+type Span struct {
+	Offset   int // byte offset in the coordinates asked for
+	Length   int // length in bytes
+	Language Language
+
+	// With Options.CharOffsets, the span in code points and
+	// in UTF-16 code units as well. Each byte of ill-formed
+	// UTF-8 counts as one code point.
+	RuneOffset, RuneLength   int
+	UTF16Offset, UTF16Length int
+}
+
+// Coordinates name the text that span offsets refer to.
+type Coordinates int
+
+const (
+	// InputCoordinates are offsets in the input, as passed in.
+	InputCoordinates Coordinates = iota
+	// TextCoordinates are offsets in the input after UTF-8 repair and,
+	// for HTML, in the text returned by ExtractText.
+	TextCoordinates
+	// LetterCoordinates are offsets in the lowercased letters
+	// returned by ExtractLetters.
+	LetterCoordinates
+)
+
+// addCharOffsets sets the rune and UTF-16 offsets of spans, which must
+// be in order, in one pass over text.
+func addCharOffsets(spans []Span, text []byte) {
+	pos, runes, units := 0, 0, 0
+	advance := func(to int) {
+		for pos < to && pos < len(text) {
+			r, n := utf8.DecodeRune(text[pos:])
+			pos += n
+			runes++
+			units++
+			if r > 0xFFFF {
+				units++ // surrogate pair
+			}
+		}
+	}
+	for i := range spans {
+		s := &spans[i]
+		advance(s.Offset)
+		s.RuneOffset, s.UTF16Offset = runes, units
+		advance(s.Offset + s.Length)
+		s.RuneLength, s.UTF16Length = runes-s.RuneOffset, units-s.UTF16Offset
+	}
+}
+
+// appendSpan appends s to spans, merging it with the last span
+// if they are adjacent and in the same language.
+func appendSpan(spans []Span, s Span) []Span {
+	if s.Length <= 0 {
+		return spans
+	}
+	if k := len(spans) - 1; k >= 0 && spans[k].Language == s.Language &&
+		spans[k].Offset+spans[k].Length == s.Offset {
+		spans[k].Length += s.Length
+		return spans
+	}
+	return append(spans, s)
+}
+
+// mapSpans maps the offsets of spans with f in place.
+// Spans that become empty are removed.
+func mapSpans(spans []Span, f func(int) int) []Span {
+	out := spans[:0]
+	for _, s := range spans {
+		start, end := f(s.Offset), f(s.Offset+s.Length)
+		out = appendSpan(out, Span{Offset: start, Length: end - start, Language: s.Language})
+	}
+	return out
+}
@@ -0,0 +1,67 @@
+package cld2
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+func TestMapSpans(t *testing.T) {
+	spans := []Span{
+		{Offset: 0, Length: 10, Language: GERMAN},
+		{Offset: 10, Length: 5, Language: UNKNOWN_LANGUAGE},
+		{Offset: 15, Length: 20, Language: FRENCH},
+		{Offset: 35, Length: 20, Language: FRENCH},
+	}
+	// Drop bytes 10..15, shift the rest to the left by 5.
+	got := mapSpans(spans, func(off int) int {
+		if off < 10 {
+			return off
+		} else if off < 15 {
+			return 10
+		}
+		return off - 5
+	})
+	want := []Span{
+		{Offset: 0, Length: 10, Language: GERMAN},
+		{Offset: 10, Length: 40, Language: FRENCH},
+	}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("want %+v, got %+v", want, got)
+	}
+}
+
+func TestAddCharOffsets(t *testing.T) {
+	// Emoji and CJK Extension B are outside the BMP: one rune,
+	// four bytes and two UTF-16 code units each.
+	text := []byte("Hi 😀 there. 𠀀𠀁 中文 ok\xff!")
+	var spans []Span
+	for _, part := range []string{"Hi 😀 there. ", "𠀀𠀁 中文 ", "ok\xff!"} {
+		off := strings.Index(string(text), part)
+		spans = append(spans, Span{Offset: off, Length: len(part)})
+	}
+	addCharOffsets(spans, text)
+	want := []Span{
+		{Offset: 0, Length: 15, RuneOffset: 0, RuneLength: 12, UTF16Offset: 0, UTF16Length: 13},
+		{Offset: 15, Length: 16, RuneOffset: 12, RuneLength: 6, UTF16Offset: 13, UTF16Length: 8},
+		{Offset: 31, Length: 4, RuneOffset: 18, RuneLength: 4, UTF16Offset: 21, UTF16Length: 4},
+	}
+	for i := range want {
+		if spans[i] != want[i] {
+			t.Errorf("span %d: want %+v, got %+v", i, want[i], spans[i])
+		}
+	}
+
+	// Cross-check against the standard library.
+	for _, s := range spans[:2] {
+		before := string(text[:s.Offset])
+		in := string(text[s.Offset : s.Offset+s.Length])
+		if n := utf8.RuneCountInString(before); n != s.RuneOffset {
+			t.Errorf("want rune offset %d, got %d", n, s.RuneOffset)
+		}
+		if n := len(utf16.Encode([]rune(in))); n != s.UTF16Length {
+			t.Errorf("want UTF-16 length %d, got %d", n, s.UTF16Length)
+		}
+	}
+}
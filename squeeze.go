@@ -0,0 +1,233 @@
+package cld2
+
+// The cheap predictor and the squeeze thresholds below are ported from
+// compact_lang_det_impl.cc, where CLD2 uses them to drop repetitive and
+// space-dense text before scoring.
+
+const (
+	squeezeChunkSize     = 48 // kChunksizeDefault: squeeze 48-byte chunks
+	spacesThreshPercent  = 25 // squeeze if >= 25% spaces
+	predictThreshPercent = 40 // squeeze if >= 40% predicted
+	predictionTableSize  = 4096
+)
+
+// predictor guesses each character from a hash of the ones before it,
+// so repetitive text is predicted well. It is CountPredictedBytes with
+// its hash and table.
+type predictor struct {
+	hash uint32
+	tbl  [predictionTableSize]uint32
+}
+
+// next reads the character at the start of b, which may be ill-formed,
+// and returns its length and whether it was predicted.
+func (p *predictor) next(b []byte) (n int, ok bool) {
+	c, n := uint32(b[0]), 1
+	switch {
+	case c < 0xc0:
+		// One-byte or continuation byte
+	case c&0xe0 == 0xc0:
+		n = 2
+	case c&0xf0 == 0xe0:
+		n = 3
+	default:
+		n = 4
+	}
+	for i := 1; i < n; i++ {
+		c <<= 8
+		if i < len(b) {
+			c |= uint32(b[i])
+		}
+	}
+	ok = p.tbl[p.hash] == c
+	p.tbl[p.hash] = c
+	p.hash = (p.hash<<4 ^ c) & 0xfff
+	return n, ok
+}
+
+// predictedBytes returns how many bytes of b are predicted correctly.
+func (p *predictor) predictedBytes(b []byte) int {
+	count := 0
+	for i := 0; i < len(b); {
+		n, ok := p.next(b[i:])
+		if ok {
+			count += n
+		}
+		i += n
+	}
+	return count
+}
+
+// countSpaces4 counts the spaces in b, ignoring the last len(b)%4
+// bytes as CLD2's CountSpaces4 does.
+func countSpaces4(b []byte) int {
+	count := 0
+	for _, c := range b[:len(b)&^3] {
+		if c == ' ' {
+			count++
+		}
+	}
+	return count
+}
+
+// chunkEnd returns the end of the chunk of text starting at i,
+// moved past any continuation bytes.
+func chunkEnd(text []byte, i, size int) int {
+	end := i + size
+	if end >= len(text) {
+		return len(text)
+	}
+	for end < len(text) && text[end]&0xc0 == 0x80 {
+		end++
+	}
+	return end
+}
+
+// squeezeStats are the chunk and word counts the squeeze heuristics
+// look at.
+type squeezeStats struct {
+	chunks     int // chunks of squeezeChunkSize bytes
+	spaceDense int // chunks with at least 25% spaces
+	predicted  int // chunks with at least 40% of bytes predicted
+	words      int // space-separated words
+	repWords   int // words with more than half their bytes predicted
+}
+
+// countSqueeze counts what CheapSqueezeInplace and CheapRepWordsInplace
+// would remove from text, which should be letters as returned by
+// ExtractLetters.
+func countSqueeze(text []byte) squeezeStats {
+	var st squeezeStats
+	spaceThresh := squeezeChunkSize * spacesThreshPercent / 100
+	predictThresh := squeezeChunkSize * predictThreshPercent / 100
+	p := new(predictor)
+	for i := 0; i < len(text); {
+		end := chunkEnd(text, i, squeezeChunkSize)
+		st.chunks++
+		if countSpaces4(text[i:end]) >= spaceThresh {
+			st.spaceDense++
+		}
+		if p.predictedBytes(text[i:end]) >= predictThresh {
+			st.predicted++
+		}
+		i = end
+	}
+
+	p = new(predictor)
+	good, length := 0, 0
+	for i := 0; i < len(text); {
+		if text[i] == ' ' {
+			if length > 0 {
+				st.words++
+				if good*2 > length {
+					st.repWords++
+				}
+			}
+			good, length = 0, 0
+		}
+		n, ok := p.next(text[i:])
+		if text[i] != ' ' {
+			length += n
+			if ok {
+				good += n
+			}
+		}
+		i += n
+	}
+	if length > 0 {
+		st.words++
+		if good*2 > length {
+			st.repWords++
+		}
+	}
+	return st
+}
+
+// Range is a range of bytes in a text.
+type Range struct {
+	Offset int
+	Length int
+}
+
+// Squeeze removes chunks of text that are mostly spaces or highly
+// repetitive, as CLD2 does before scoring very repetitive documents,
+// and reports the byte ranges of text it removed. The text is looked at
+// in chunks of chunkSize bytes, or 48 if chunkSize <= 0. A chunk is
+// removed if a quarter of it is spaces or if a cheap predictor guesses
+// 40% of its bytes. Cuts are moved to word boundaries where possible.
+//
+// Squeeze is a port of CheapSqueezeInplace in compact_lang_det_impl.cc,
+// except that it doesn't pad its output as CLD2's buffers need.
+func Squeeze(text []byte, chunkSize int) (cleaned []byte, removed []Range) {
+	if chunkSize <= 0 {
+		chunkSize = squeezeChunkSize
+	}
+	spaceThresh := chunkSize * spacesThreshPercent / 100
+	predictThresh := chunkSize * predictThreshPercent / 100
+	p := new(predictor)
+	out := make([]byte, 0, len(text))
+	skipping := false
+	skipStart := 0
+	kept := 0 // bytes kept since the last removal, all from text just before i
+	for i := 0; i < len(text); {
+		end := chunkEnd(text, i, chunkSize)
+		chunk := text[i:end]
+		spaces := countSpaces4(chunk)
+		predicted := p.predictedBytes(chunk)
+		if spaces >= spaceThresh || predicted >= predictThresh {
+			if !skipping {
+				// Keeping-to-skipping transition; do it at a space
+				n := backscanToSpace(out, kept)
+				out = out[:len(out)-n]
+				skipStart = i - n
+				skipping = true
+			}
+		} else {
+			if skipping {
+				// Skipping-to-keeping transition; do it at a space
+				n := forwardscanToSpace(chunk)
+				chunk = chunk[n:]
+				removed = append(removed, Range{Offset: skipStart, Length: i + n - skipStart})
+				skipping = false
+				kept = 0
+			}
+			out = append(out, chunk...)
+			kept += len(chunk)
+		}
+		i = end
+	}
+	if skipping {
+		removed = append(removed, Range{Offset: skipStart, Length: len(text) - skipStart})
+	}
+	return out, removed
+}
+
+// maxSpaceScan is how far Squeeze looks for a space to cut at.
+const maxSpaceScan = 32
+
+// backscanToSpace returns how many bytes to cut from the end of b, at
+// most limit, so that b ends in a space. It returns 0 if there is no
+// space close enough.
+func backscanToSpace(b []byte, limit int) int {
+	if limit > maxSpaceScan {
+		limit = maxSpaceScan
+	}
+	for n := 0; n < limit && n < len(b); n++ {
+		if b[len(b)-n-1] == ' ' {
+			return n
+		}
+	}
+	return 0
+}
+
+// forwardscanToSpace returns how many bytes to skip at the start of b
+// to get past the next space. It returns 0 if there is no space close
+// enough.
+func forwardscanToSpace(b []byte) int {
+	for n := 0; n < maxSpaceScan && n < len(b); n++ {
+		if b[n] == ' ' {
+			return n + 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,31 @@
+package cld2
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSqueeze(t *testing.T) {
+	junk := strings.Repeat("ab ", 40)
+	tail := " the quick brown fox jumps over the lazy dog while five boxing wizards jump quickly"
+	text := daLetters + junk + tail
+	cleaned, removed := Squeeze([]byte(text), 0)
+	if len(removed) != 1 {
+		t.Fatalf("want one removed range, got %+v", removed)
+	}
+	r := removed[0]
+	if r.Offset > len(daLetters)+squeezeChunkSize || r.Offset+r.Length < len(daLetters)+len(junk) || r.Offset+r.Length > len(text)-len(tail)+maxSpaceScan {
+		t.Errorf("want the junk at %d..%d removed, got %+v", len(daLetters), len(daLetters)+len(junk), r)
+	}
+	if want := text[:r.Offset] + text[r.Offset+r.Length:]; string(cleaned) != want {
+		t.Errorf("want cleaned text to be the text without the removed range,\nwant %q\n got %q", want, cleaned)
+	}
+	if text[r.Offset-1] != ' ' || text[r.Offset+r.Length-1] != ' ' {
+		t.Errorf("want cuts at spaces, got %q", text[r.Offset:r.Offset+r.Length])
+	}
+
+	cleaned, removed = Squeeze([]byte(daLetters), 0)
+	if string(cleaned) != daLetters || len(removed) != 0 {
+		t.Errorf("want normal text kept, got %q %+v", cleaned, removed)
+	}
+}
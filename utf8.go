@@ -0,0 +1,144 @@
+package cld2
+
+import (
+	"errors"
+	"unicode/utf8"
+)
+
+// CLD2 expects interchange-valid UTF-8. The checks below follow
+// FixUnicodeValue in fixunicodevalue.cc: surrogates and non-characters
+// are not interchange valid even when they are well-formed.
+
+// ErrInvalidUTF8 is returned in UTF8Reject mode when the text
+// is not interchange-valid UTF-8.
+var ErrInvalidUTF8 = errors.New("cld2: text is not valid UTF-8")
+
+// UTF8Mode selects what happens to text that isn't valid UTF-8.
+type UTF8Mode int
+
+const (
+	UTF8Replace UTF8Mode = iota // replace each bad sequence with U+FFFD
+	UTF8Drop                    // remove bad sequences
+	UTF8Reject                  // fail with ErrInvalidUTF8
+)
+
+// UTF8Report counts the problems found in a text.
+type UTF8Report struct {
+	InvalidSequences int `json:"invalid_sequences"` // ill-formed sequences, each maximal subpart once
+	Surrogates       int `json:"surrogates"`        // U+D800..U+DFFF encoded as UTF-8 (CESU-8)
+	NonCharacters    int `json:"noncharacters"`     // U+FDD0..U+FDEF, U+xxFFFE and U+xxFFFF
+	Bytes            int `json:"bytes"`             // input bytes in all of the above
+}
+
+// Damaged reports whether any problem was found.
+func (r UTF8Report) Damaged() bool {
+	return r.Bytes > 0
+}
+
+// RepairUTF8 checks that text is interchange-valid UTF-8 and repairs
+// it according to mode. The text is returned unchanged if it is valid
+// or if mode is UTF8Reject, in which case the error is ErrInvalidUTF8.
+func RepairUTF8(text []byte, mode UTF8Mode) ([]byte, UTF8Report, error) {
+	return repairUTF8(text, mode, nil)
+}
+
+// repairUTF8 is RepairUTF8, also recording the changes in m if not nil.
+func repairUTF8(text []byte, mode UTF8Mode, m *OffsetMap) ([]byte, UTF8Report, error) {
+	var rep UTF8Report
+	var out []byte
+	last := 0 // start of text not yet copied to out
+	for i := 0; i < len(text); {
+		if text[i] < utf8.RuneSelf {
+			i++
+			continue
+		}
+		r, n := utf8.DecodeRune(text[i:])
+		switch {
+		case r == utf8.RuneError && n == 1:
+			if n = surrogateLen(text[i:]); n > 0 {
+				rep.Surrogates++
+			} else {
+				n = maximalSubpart(text[i:])
+				rep.InvalidSequences++
+			}
+		case isNonCharacter(r):
+			rep.NonCharacters++
+		default:
+			i += n
+			continue
+		}
+		rep.Bytes += n
+		if mode != UTF8Reject {
+			out = append(out, text[last:i]...)
+			put := 0
+			if mode == UTF8Replace {
+				out = append(out, "�"...)
+				put = len("�")
+			}
+			if m != nil {
+				m.Copy(i - last)
+				m.Insert(put)
+				m.Delete(n)
+			}
+			last = i + n
+		}
+		i += n
+	}
+	if !rep.Damaged() {
+		return text, rep, nil
+	}
+	if mode == UTF8Reject {
+		return text, rep, ErrInvalidUTF8
+	}
+	return append(out, text[last:]...), rep, nil
+}
+
+// surrogateLen returns 3 if p starts with a UTF-8 encoded surrogate,
+// and 0 otherwise.
+func surrogateLen(p []byte) int {
+	if len(p) >= 3 && p[0] == 0xED && p[1]&0xE0 == 0xA0 && p[2]&0xC0 == 0x80 {
+		return 3
+	}
+	return 0
+}
+
+// maximalSubpart returns the length of the ill-formed sequence at the
+// start of p: the longest prefix of a well-formed sequence, at least one
+// byte. This is the Unicode recommendation for U+FFFD substitution.
+func maximalSubpart(p []byte) int {
+	var n int
+	lo, hi := byte(0x80), byte(0xBF) // range of the second byte
+	switch c := p[0]; {
+	case c >= 0xC2 && c <= 0xDF:
+		n = 2
+	case c >= 0xE0 && c <= 0xEF:
+		n = 3
+		if c == 0xE0 {
+			lo = 0xA0
+		} else if c == 0xED {
+			hi = 0x9F
+		}
+	case c >= 0xF0 && c <= 0xF4:
+		n = 4
+		if c == 0xF0 {
+			lo = 0x90
+		} else if c == 0xF4 {
+			hi = 0x8F
+		}
+	default:
+		return 1
+	}
+	i := 1
+	for ; i < n && i < len(p); i++ {
+		if p[i] < lo || p[i] > hi {
+			break
+		}
+		lo, hi = 0x80, 0xBF
+	}
+	return i
+}
+
+// isNonCharacter reports whether r is one of the 66 Unicode non-characters.
+func isNonCharacter(r rune) bool {
+	return r >= 0xFDD0 && r <= 0xFDEF || r&0xFFFE == 0xFFFE
+}
@@ -0,0 +1,51 @@
+package cld2
+
+import "testing"
+
+func TestRepairUTF8(t *testing.T) {
+	for _, tc := range []struct {
+		text          string
+		replace, drop string
+		want          UTF8Report
+	}{
+		{"plain ascii", "plain ascii", "plain ascii", UTF8Report{}},
+		{"valid ÆØÅ 日本 😀", "valid ÆØÅ 日本 😀", "valid ÆØÅ 日本 😀", UTF8Report{}},
+		{"a\xffb", "a�b", "ab", UTF8Report{InvalidSequences: 1, Bytes: 1}},
+		{"Latin-1 caf\xe9!", "Latin-1 caf�!", "Latin-1 caf!", UTF8Report{InvalidSequences: 1, Bytes: 1}},
+		// A truncated sequence is one maximal subpart.
+		{"x\xe6\x97", "x�", "x", UTF8Report{InvalidSequences: 1, Bytes: 2}},
+		{"\xf0\x9f\x98 end", "� end", " end", UTF8Report{InvalidSequences: 1, Bytes: 3}},
+		// Overlong encodings are one subpart per byte.
+		{"\xc0\xaf", "��", "", UTF8Report{InvalidSequences: 2, Bytes: 2}},
+		{"\xe0\x80\xaf", "���", "", UTF8Report{InvalidSequences: 3, Bytes: 3}},
+		{"s\xed\xa0\xbd\xed\xb8\x80s", "s��s", "ss", UTF8Report{Surrogates: 2, Bytes: 6}},
+		{"n\xef\xb7\x90n\xef\xbf\xben\U0010FFFF", "n�n�n�", "nnn", UTF8Report{NonCharacters: 3, Bytes: 10}},
+	} {
+		got, rep, err := RepairUTF8([]byte(tc.text), UTF8Replace)
+		if err != nil || string(got) != tc.replace || rep != tc.want {
+			t.Errorf("replace %q: want %q %+v, got %q %+v (%v)", tc.text, tc.replace, tc.want, got, rep, err)
+		}
+		got, rep, err = RepairUTF8([]byte(tc.text), UTF8Drop)
+		if err != nil || string(got) != tc.drop || rep != tc.want {
+			t.Errorf("drop %q: want %q %+v, got %q %+v (%v)", tc.text, tc.drop, tc.want, got, rep, err)
+		}
+		got, rep, err = RepairUTF8([]byte(tc.text), UTF8Reject)
+		if string(got) != tc.text || rep != tc.want || (err != nil) != tc.want.Damaged() {
+			t.Errorf("reject %q: want %+v, got %q %+v (%v)", tc.text, tc.want, got, rep, err)
+		}
+	}
+}
+
+func TestRepairUTF8Offsets(t *testing.T) {
+	text := []byte("ab\xffcd\xe6\x97ef")
+	var m OffsetMap
+	got, _, _ := repairUTF8(text, UTF8Replace, &m)
+	if string(got) != "ab�cd�ef" {
+		t.Fatalf("want 'ab�cd�ef', got %q", got)
+	}
+	for prim, want := range map[int]int{0: 0, 2: 2, 4: 2, 5: 3, 6: 4, 7: 5, 9: 5, 10: 7, 12: 9} {
+		if got := m.MapBack(prim); got != want {
+			t.Errorf("MapBack(%d): want %d, got %d", prim, want, got)
+		}
+	}
+}